@@ -0,0 +1,68 @@
+// Command breachlist converts a password breach dump into the compact list format read
+// by the API (see internal/breach).
+//
+// Input lines are either SHA-1 digests in hex, optionally followed by ":count" as in the
+// Have I Been Pwned downloads, or plain passwords when -plain is set.
+//
+//	breachlist -o breached.pdbl < pwned-passwords-sha1.txt
+//	breachlist -plain -o breached.pdbl < rockyou.txt
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hwangseonu/paperless.dev/internal/breach"
+)
+
+func main() {
+	output := flag.String("o", "breached.pdbl", "output file")
+	prefix := flag.Int("prefix", breach.DefaultPrefix, "number of digest bytes to keep per entry")
+	plain := flag.Bool("plain", false, "input lines are plain passwords instead of SHA-1 digests")
+	flag.Parse()
+
+	var digests [][]byte
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+
+		if *plain {
+			sum := sha1.Sum([]byte(text))
+			digests = append(digests, sum[:])
+			continue
+		}
+
+		digestHex, _, _ := strings.Cut(text, ":")
+		digest, err := hex.DecodeString(digestHex)
+		if err != nil || len(digest) != sha1.Size {
+			log.Fatalf("line %d: not a SHA-1 digest", line)
+		}
+		digests = append(digests, digest)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatalln(err)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer file.Close()
+
+	if err := breach.Write(file, *prefix, digests); err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Printf("wrote %d digests to %s\n", len(digests), *output)
+}
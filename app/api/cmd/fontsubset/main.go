@@ -0,0 +1,109 @@
+// Command fontsubset writes the fallback fonts embedded by internal/export (see
+// internal/export/fonts/README.md).
+//
+// It instances a variable font at the given weight, keeps the glyphs for the given
+// characters and writes them as a TrueType font, converting CFF outlines to quadratic
+// curves on the way. fpdf can only embed TrueType outlines, so the OpenType/CFF releases
+// of Noto Sans CJK cannot be used as they are.
+//
+//	fontsubset -wght 400 -o NotoSansCJKkr-Regular-Subset.ttf NotoSansCJKjp-VF.otf
+//	fontsubset -wght 700 -o NotoSansCJKkr-Bold-Subset.ttf NotoSansCJKjp-VF.otf
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
+	"github.com/go-text/typesetting/font/opentype/tables"
+)
+
+// defaultRanges covers Korean text: Hangul syllables and jamo, CJK punctuation and the
+// full-width forms. Spaces are included because text runs keep the spaces between
+// words in the fallback font.
+const defaultRanges = "0020,00A0,1100-11FF,3000-303F,3130-318F,AC00-D7A3,FF00-FFEF"
+
+func main() {
+	output := flag.String("o", "subset.ttf", "output file")
+	weight := flag.Float64("wght", 400, "weight to instance the variable font at")
+	ranges := flag.String("ranges", defaultRanges, "comma separated code points and ranges to keep, in hex")
+	family := flag.String("family", "Noto Sans CJK KR Subset", "family name of the output font")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		log.Fatalln("usage: fontsubset [flags] font.otf")
+	}
+
+	runes, err := parseRanges(*ranges)
+	if err != nil {
+		log.Fatalln("invalid -ranges:", err)
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer file.Close()
+
+	loader, err := ot.NewLoader(file)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	source, err := font.NewFont(loader)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	raw, err := loader.RawTable(ot.MustNewTag("name"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	names, _, err := tables.ParseName(raw)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	face := font.NewFace(source)
+	face.SetCoords(source.NormalizeVariations([]float32{float32(*weight)}))
+
+	subset := newSubset(face, names, *family, int(*weight))
+	for _, r := range runes {
+		subset.add(r)
+	}
+
+	if err := os.WriteFile(*output, subset.encode(), 0o644); err != nil {
+		log.Fatalln(err)
+	}
+	log.Printf("wrote %d glyphs for %d characters to %s", len(subset.glyphs), len(subset.cmap), *output)
+}
+
+// parseRanges parses a list such as "0020,AC00-D7A3" into sorted code points.
+func parseRanges(text string) ([]rune, error) {
+	var runes []rune
+	for _, part := range strings.Split(text, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		if !isRange {
+			last = first
+		}
+
+		from, err := strconv.ParseUint(first, 16, 32)
+		if err != nil {
+			return nil, err
+		}
+		to, err := strconv.ParseUint(last, 16, 32)
+		if err != nil {
+			return nil, err
+		}
+		for r := from; r <= to; r++ {
+			runes = append(runes, rune(r))
+		}
+	}
+
+	slices.Sort(runes)
+	return slices.Compact(runes), nil
+}
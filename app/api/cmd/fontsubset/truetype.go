@@ -0,0 +1,659 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+	"unicode/utf16"
+
+	"github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
+	"github.com/go-text/typesetting/font/opentype/tables"
+)
+
+// tolerance is how far, in font units, the quadratic curves may stray from the cubic
+// curves they replace.
+const tolerance = 1.0
+
+// copiedNames are the name table entries taken over from the source font: copyright,
+// trademark, manufacturer, designer, vendor and designer URLs, license and license URL.
+var copiedNames = []tables.NameID{0, 7, 8, 9, 11, 12, 13, 14}
+
+type point struct {
+	x, y    int16
+	onCurve bool
+}
+
+type glyph struct {
+	contours [][]point
+	advance  uint16
+}
+
+func (g *glyph) bounds() (xMin, yMin, xMax, yMax int16) {
+	xMin, yMin, xMax, yMax = math.MaxInt16, math.MaxInt16, math.MinInt16, math.MinInt16
+	for _, contour := range g.contours {
+		for _, p := range contour {
+			xMin, yMin = min(xMin, p.x), min(yMin, p.y)
+			xMax, yMax = max(xMax, p.x), max(yMax, p.y)
+		}
+	}
+	if len(g.contours) == 0 {
+		return 0, 0, 0, 0
+	}
+	return xMin, yMin, xMax, yMax
+}
+
+// subset collects the glyphs of a font instance for a set of characters.
+type subset struct {
+	face   *font.Face
+	names  tables.Name
+	family string
+	weight int
+	glyphs []*glyph
+	// index maps source glyph IDs to IDs in the subset.
+	index map[font.GID]uint16
+	cmap  map[rune]uint16
+}
+
+func newSubset(face *font.Face, names tables.Name, family string, weight int) *subset {
+	s := &subset{
+		face:   face,
+		names:  names,
+		family: family,
+		weight: weight,
+		index:  map[font.GID]uint16{},
+		cmap:   map[rune]uint16{},
+	}
+	s.addGlyph(0) // .notdef
+	return s
+}
+
+// add keeps the glyph for r if the font has one.
+func (s *subset) add(r rune) {
+	gid, ok := s.face.NominalGlyph(r)
+	if !ok {
+		return
+	}
+	s.cmap[r] = s.addGlyph(gid)
+}
+
+func (s *subset) addGlyph(gid font.GID) uint16 {
+	if index, ok := s.index[gid]; ok {
+		return index
+	}
+
+	g := &glyph{advance: uint16(math.Round(float64(s.face.HorizontalAdvance(gid))))}
+	if outline, ok := s.face.GlyphDataOutline(gid); ok {
+		g.contours = quadraticContours(outline.Segments)
+	}
+
+	index := uint16(len(s.glyphs))
+	s.glyphs = append(s.glyphs, g)
+	s.index[gid] = index
+	return index
+}
+
+type vec struct{ x, y float64 }
+
+func (a vec) add(b vec) vec       { return vec{a.x + b.x, a.y + b.y} }
+func (a vec) sub(b vec) vec       { return vec{a.x - b.x, a.y - b.y} }
+func (a vec) scale(k float64) vec { return vec{a.x * k, a.y * k} }
+func (a vec) length() float64     { return math.Hypot(a.x, a.y) }
+func (a vec) round(onCurve bool) point {
+	return point{int16(math.Round(a.x)), int16(math.Round(a.y)), onCurve}
+}
+
+// quadraticContours converts an outline to TrueType contours. Cubic curves are split
+// until a single quadratic curve approximates each piece within tolerance, and the
+// contours are reversed because TrueType fills clockwise outlines where CFF fills
+// counter-clockwise ones.
+func quadraticContours(segments []ot.Segment) [][]point {
+	var contours [][]point
+	var contour []point
+	var current vec
+
+	closeContour := func() {
+		if len(contour) > 1 && contour[len(contour)-1] == contour[0] {
+			contour = contour[:len(contour)-1]
+		}
+		if len(contour) > 2 {
+			slices.Reverse(contour[1:])
+			contours = append(contours, contour)
+		}
+		contour = nil
+	}
+
+	for _, segment := range segments {
+		args := make([]vec, 3)
+		for i, arg := range segment.ArgsSlice() {
+			args[i] = vec{float64(arg.X), float64(arg.Y)}
+		}
+
+		switch segment.Op {
+		case ot.SegmentOpMoveTo:
+			closeContour()
+			contour = append(contour, args[0].round(true))
+			current = args[0]
+		case ot.SegmentOpLineTo:
+			contour = append(contour, args[0].round(true))
+			current = args[0]
+		case ot.SegmentOpQuadTo:
+			contour = append(contour, args[0].round(false), args[1].round(true))
+			current = args[1]
+		case ot.SegmentOpCubeTo:
+			contour = append(contour, cubicToQuadratics(current, args[0], args[1], args[2])...)
+			current = args[2]
+		}
+	}
+	closeContour()
+
+	return contours
+}
+
+// cubicToQuadratics returns the control and end points of the quadratic curves that
+// approximate the cubic curve from p0 to p3.
+func cubicToQuadratics(p0, c1, c2, p3 vec) []point {
+	// The midpoint approximation of a cubic is off by at most sqrt(3)/36 times the
+	// length of its third difference, which shrinks with the cube of the split count.
+	third := p3.sub(c2.scale(3)).add(c1.scale(3)).sub(p0)
+	n := max(1, int(math.Ceil(math.Cbrt(third.length()*math.Sqrt(3)/36/tolerance))))
+
+	at := func(t float64) vec {
+		u := 1 - t
+		return p0.scale(u * u * u).add(c1.scale(3 * u * u * t)).add(c2.scale(3 * u * t * t)).add(p3.scale(t * t * t))
+	}
+	tangent := func(t float64) vec {
+		u := 1 - t
+		return c1.sub(p0).scale(3 * u * u).add(c2.sub(c1).scale(6 * u * t)).add(p3.sub(c2).scale(3 * t * t))
+	}
+
+	points := make([]point, 0, 2*n)
+	step := 1 / float64(n)
+	for i := range n {
+		t0, t1 := float64(i)*step, float64(i+1)*step
+		q0, q3 := at(t0), at(t1)
+		q1 := q0.add(tangent(t0).scale(step / 3))
+		q2 := q3.sub(tangent(t1).scale(step / 3))
+
+		control := q1.add(q2).scale(3).sub(q0).sub(q3).scale(0.25)
+		points = append(points, control.round(false), q3.round(true))
+	}
+	return points
+}
+
+// encode writes the subset as a TrueType font with the tables fpdf reads.
+func (s *subset) encode() []byte {
+	glyf, loca := s.glyfTable()
+
+	fontTables := map[string][]byte{
+		"OS/2": s.os2Table(),
+		"cmap": s.cmapTable(),
+		"glyf": glyf,
+		"head": s.headTable(),
+		"hhea": s.hheaTable(),
+		"hmtx": s.hmtxTable(),
+		"loca": loca,
+		"maxp": s.maxpTable(),
+		"name": s.nameTable(),
+		"post": postTable(),
+	}
+	return writeFont(fontTables)
+}
+
+func (s *subset) glyfTable() (glyf, loca []byte) {
+	for _, g := range s.glyphs {
+		loca = binary.BigEndian.AppendUint32(loca, uint32(len(glyf)))
+		glyf = appendGlyph(glyf, g)
+		for len(glyf)%4 != 0 {
+			glyf = append(glyf, 0)
+		}
+	}
+	loca = binary.BigEndian.AppendUint32(loca, uint32(len(glyf)))
+	return glyf, loca
+}
+
+// appendGlyph encodes a simple glyph without instructions. Empty glyphs take no space.
+func appendGlyph(b []byte, g *glyph) []byte {
+	if len(g.contours) == 0 {
+		return b
+	}
+
+	xMin, yMin, xMax, yMax := g.bounds()
+	b = binary.BigEndian.AppendUint16(b, uint16(len(g.contours)))
+	for _, v := range []int16{xMin, yMin, xMax, yMax} {
+		b = binary.BigEndian.AppendUint16(b, uint16(v))
+	}
+
+	end := -1
+	for _, contour := range g.contours {
+		end += len(contour)
+		b = binary.BigEndian.AppendUint16(b, uint16(end))
+	}
+	b = binary.BigEndian.AppendUint16(b, 0) // instructionLength
+
+	const (
+		onCurve  = 0x01
+		xShort   = 0x02
+		yShort   = 0x04
+		repeat   = 0x08
+		xSame    = 0x10 // or positive, when xShort is set
+		ySame    = 0x20 // or positive, when yShort is set
+		maxShort = 0xff
+	)
+
+	var flags []byte
+	var xs, ys []byte
+	var last point
+	var lastFlag byte
+	repeats := 0
+	for _, contour := range g.contours {
+		for _, p := range contour {
+			var flag byte
+			if p.onCurve {
+				flag |= onCurve
+			}
+
+			dx, dy := int(p.x)-int(last.x), int(p.y)-int(last.y)
+			switch {
+			case dx == 0:
+				flag |= xSame
+			case dx >= -maxShort && dx <= maxShort:
+				flag |= xShort
+				if dx > 0 {
+					flag |= xSame
+				}
+				xs = append(xs, byte(abs(dx)))
+			default:
+				xs = binary.BigEndian.AppendUint16(xs, uint16(int16(dx)))
+			}
+			switch {
+			case dy == 0:
+				flag |= ySame
+			case dy >= -maxShort && dy <= maxShort:
+				flag |= yShort
+				if dy > 0 {
+					flag |= ySame
+				}
+				ys = append(ys, byte(abs(dy)))
+			default:
+				ys = binary.BigEndian.AppendUint16(ys, uint16(int16(dy)))
+			}
+
+			// Runs of equal flags are written once with a repeat count.
+			switch {
+			case len(flags) == 0, flag != lastFlag, repeats == maxShort:
+				flags = append(flags, flag)
+				lastFlag, repeats = flag, 0
+			case repeats == 0:
+				flags[len(flags)-1] |= repeat
+				flags = append(flags, 1)
+				repeats = 1
+			default:
+				flags[len(flags)-1]++
+				repeats++
+			}
+			last = p
+		}
+	}
+
+	b = append(b, flags...)
+	b = append(b, xs...)
+	return append(b, ys...)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func (s *subset) bounds() (xMin, yMin, xMax, yMax int16) {
+	xMin, yMin, xMax, yMax = math.MaxInt16, math.MaxInt16, math.MinInt16, math.MinInt16
+	for _, g := range s.glyphs {
+		if len(g.contours) == 0 {
+			continue
+		}
+		gxMin, gyMin, gxMax, gyMax := g.bounds()
+		xMin, yMin = min(xMin, gxMin), min(yMin, gyMin)
+		xMax, yMax = max(xMax, gxMax), max(yMax, gyMax)
+	}
+	return xMin, yMin, xMax, yMax
+}
+
+func (s *subset) bold() bool { return s.weight >= 600 }
+
+func (s *subset) headTable() []byte {
+	xMin, yMin, xMax, yMax := s.bounds()
+	var macStyle uint16
+	if s.bold() {
+		macStyle = 1
+	}
+
+	var b []byte
+	b = binary.BigEndian.AppendUint32(b, 0x00010000)    // version
+	b = binary.BigEndian.AppendUint32(b, 0x00010000)    // fontRevision
+	b = binary.BigEndian.AppendUint32(b, 0)             // checksumAdjustment, set by writeFont
+	b = binary.BigEndian.AppendUint32(b, 0x5f0f3cf5)    // magicNumber
+	b = binary.BigEndian.AppendUint16(b, 0x0003)        // flags: baseline and left sidebearing at 0
+	b = binary.BigEndian.AppendUint16(b, s.face.Upem()) // unitsPerEm
+	b = binary.BigEndian.AppendUint64(b, 0)             // created
+	b = binary.BigEndian.AppendUint64(b, 0)             // modified
+	for _, v := range []int16{xMin, yMin, xMax, yMax} {
+		b = binary.BigEndian.AppendUint16(b, uint16(v))
+	}
+	b = binary.BigEndian.AppendUint16(b, macStyle)
+	b = binary.BigEndian.AppendUint16(b, 8) // lowestRecPPEM
+	b = binary.BigEndian.AppendUint16(b, 2) // fontDirectionHint
+	b = binary.BigEndian.AppendUint16(b, 1) // indexToLocFormat: 32-bit offsets
+	return binary.BigEndian.AppendUint16(b, 0)
+}
+
+func (s *subset) hheaTable() []byte {
+	extents, _ := s.face.FontHExtents()
+
+	var advanceMax uint16
+	minLeft, minRight, maxExtent := int16(math.MaxInt16), int16(math.MaxInt16), int16(math.MinInt16)
+	for _, g := range s.glyphs {
+		advanceMax = max(advanceMax, g.advance)
+		if len(g.contours) == 0 {
+			continue
+		}
+		xMin, _, xMax, _ := g.bounds()
+		minLeft = min(minLeft, xMin)
+		minRight = min(minRight, int16(g.advance)-xMax)
+		maxExtent = max(maxExtent, xMax)
+	}
+
+	var b []byte
+	b = binary.BigEndian.AppendUint32(b, 0x00010000)
+	for _, v := range []float32{extents.Ascender, extents.Descender, extents.LineGap} {
+		b = binary.BigEndian.AppendUint16(b, uint16(int16(math.Round(float64(v)))))
+	}
+	b = binary.BigEndian.AppendUint16(b, advanceMax)
+	for _, v := range []int16{minLeft, minRight, maxExtent, 1, 0, 0, 0, 0, 0, 0, 0} {
+		b = binary.BigEndian.AppendUint16(b, uint16(v)) // through caretSlopeRise, caretSlopeRun, caretOffset and reserved
+	}
+	return binary.BigEndian.AppendUint16(b, uint16(len(s.glyphs))) // numberOfHMetrics
+}
+
+func (s *subset) hmtxTable() []byte {
+	var b []byte
+	for _, g := range s.glyphs {
+		xMin, _, _, _ := g.bounds()
+		b = binary.BigEndian.AppendUint16(b, g.advance)
+		b = binary.BigEndian.AppendUint16(b, uint16(xMin))
+	}
+	return b
+}
+
+func (s *subset) maxpTable() []byte {
+	var maxPoints, maxContours uint16
+	for _, g := range s.glyphs {
+		points := 0
+		for _, contour := range g.contours {
+			points += len(contour)
+		}
+		maxPoints = max(maxPoints, uint16(points))
+		maxContours = max(maxContours, uint16(len(g.contours)))
+	}
+
+	var b []byte
+	b = binary.BigEndian.AppendUint32(b, 0x00010000)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s.glyphs)))
+	b = binary.BigEndian.AppendUint16(b, maxPoints)
+	b = binary.BigEndian.AppendUint16(b, maxContours)
+	// No composite glyphs, hinting programs or storage.
+	b = append(b, make([]byte, 4)...)
+	b = binary.BigEndian.AppendUint16(b, 2) // maxZones
+	return append(b, make([]byte, 16)...)
+}
+
+func (s *subset) os2Table() []byte {
+	extents, _ := s.face.FontHExtents()
+	ascender := int16(math.Round(float64(extents.Ascender)))
+	descender := int16(math.Round(float64(extents.Descender)))
+	capHeight := int16(math.Round(float64(s.face.LineMetric(font.CapHeight))))
+	xHeight := int16(math.Round(float64(s.face.LineMetric(font.XHeight))))
+
+	var advances int
+	for _, g := range s.glyphs {
+		advances += int(g.advance)
+	}
+
+	runes := make([]rune, 0, len(s.cmap))
+	for r := range s.cmap {
+		runes = append(runes, r)
+	}
+	slices.Sort(runes)
+
+	fsSelection := uint16(0x0040) // regular
+	if s.bold() {
+		fsSelection = 0x0020
+	}
+
+	var b []byte
+	b = binary.BigEndian.AppendUint16(b, 4) // version
+	b = binary.BigEndian.AppendUint16(b, uint16(advances/len(s.glyphs)))
+	b = binary.BigEndian.AppendUint16(b, uint16(s.weight))
+	b = binary.BigEndian.AppendUint16(b, 5) // usWidthClass: medium
+	b = binary.BigEndian.AppendUint16(b, 0) // fsType: installable embedding
+	upem := int16(s.face.Upem())
+	for _, v := range []int16{
+		upem * 65 / 100, upem * 60 / 100, 0, upem * 7 / 100, // subscript size and offset
+		upem * 65 / 100, upem * 60 / 100, 0, upem * 48 / 100, // superscript size and offset
+		upem * 5 / 100, upem * 25 / 100, // strikeout size and position
+		0, // sFamilyClass
+	} {
+		b = binary.BigEndian.AppendUint16(b, uint16(v))
+	}
+	b = append(b, make([]byte, 10)...) // panose
+	// Unicode ranges: Hangul Jamo (28), CJK Symbols and Punctuation (48), Hangul
+	// Compatibility Jamo (52), Hangul Syllables (56) and Halfwidth and Fullwidth Forms (68).
+	b = binary.BigEndian.AppendUint32(b, 1<<28)
+	b = binary.BigEndian.AppendUint32(b, 1<<(48-32)|1<<(52-32)|1<<(56-32))
+	b = binary.BigEndian.AppendUint32(b, 1<<(68-64))
+	b = binary.BigEndian.AppendUint32(b, 0)
+	b = append(b, "NONE"...) // achVendID
+	b = binary.BigEndian.AppendUint16(b, fsSelection)
+	b = binary.BigEndian.AppendUint16(b, uint16(min(runes[0], 0xffff)))
+	b = binary.BigEndian.AppendUint16(b, uint16(min(runes[len(runes)-1], 0xffff)))
+	for _, v := range []int16{ascender, descender, 0, ascender, -descender} {
+		b = binary.BigEndian.AppendUint16(b, uint16(v)) // typo metrics and win metrics
+	}
+	b = binary.BigEndian.AppendUint32(b, 1<<19) // ulCodePageRange1: Korean Wansung
+	b = binary.BigEndian.AppendUint32(b, 0)
+	for _, v := range []int16{xHeight, capHeight, 0, 0x20, 0} {
+		b = binary.BigEndian.AppendUint16(b, uint16(v)) // through usDefaultChar, usBreakChar and usMaxContext
+	}
+	return b
+}
+
+// cmapTable maps the characters with a format 4 subtable, one segment for every run of
+// consecutive characters with consecutive glyphs.
+func (s *subset) cmapTable() []byte {
+	runes := make([]rune, 0, len(s.cmap))
+	for r := range s.cmap {
+		if r <= 0xffff {
+			runes = append(runes, r)
+		}
+	}
+	slices.Sort(runes)
+
+	type segment struct{ start, end rune }
+	var segments []segment
+	for _, r := range runes {
+		if n := len(segments); n > 0 && segments[n-1].end == r-1 && s.cmap[r-1] == s.cmap[r]-1 {
+			segments[n-1].end = r
+			continue
+		}
+		segments = append(segments, segment{r, r})
+	}
+	segments = append(segments, segment{0xffff, 0xffff})
+
+	segCountX2 := uint16(2 * len(segments))
+	searchRange := uint16(2)
+	entrySelector := uint16(0)
+	for searchRange*2 <= segCountX2 {
+		searchRange *= 2
+		entrySelector++
+	}
+
+	var sub []byte
+	sub = binary.BigEndian.AppendUint16(sub, 4) // format
+	sub = binary.BigEndian.AppendUint16(sub, uint16(16+8*len(segments)))
+	sub = binary.BigEndian.AppendUint16(sub, 0) // language
+	sub = binary.BigEndian.AppendUint16(sub, segCountX2)
+	sub = binary.BigEndian.AppendUint16(sub, searchRange)
+	sub = binary.BigEndian.AppendUint16(sub, entrySelector)
+	sub = binary.BigEndian.AppendUint16(sub, segCountX2-searchRange)
+	for _, seg := range segments {
+		sub = binary.BigEndian.AppendUint16(sub, uint16(seg.end))
+	}
+	sub = binary.BigEndian.AppendUint16(sub, 0) // reservedPad
+	for _, seg := range segments {
+		sub = binary.BigEndian.AppendUint16(sub, uint16(seg.start))
+	}
+	for _, seg := range segments {
+		delta := uint16(1) // maps 0xffff to .notdef
+		if seg.start != 0xffff {
+			delta = s.cmap[seg.start] - uint16(seg.start)
+		}
+		sub = binary.BigEndian.AppendUint16(sub, delta)
+	}
+	sub = append(sub, make([]byte, 2*len(segments))...) // idRangeOffsets
+
+	// Unicode BMP and Windows Unicode BMP share the subtable.
+	var b []byte
+	b = binary.BigEndian.AppendUint16(b, 0) // version
+	b = binary.BigEndian.AppendUint16(b, 2)
+	for _, platform := range [][2]uint16{{0, 3}, {3, 1}} {
+		b = binary.BigEndian.AppendUint16(b, platform[0])
+		b = binary.BigEndian.AppendUint16(b, platform[1])
+		b = binary.BigEndian.AppendUint32(b, 4+8*2)
+	}
+	return append(b, sub...)
+}
+
+func (s *subset) nameTable() []byte {
+	style := "Regular"
+	if s.bold() {
+		style = "Bold"
+	}
+	postScript := []rune{}
+	for _, r := range s.family + "-" + style {
+		if r != ' ' {
+			postScript = append(postScript, r)
+		}
+	}
+
+	names := map[tables.NameID]string{
+		1: s.family,
+		2: style,
+		3: string(postScript),
+		4: s.family + " " + style,
+		5: "Version 1.000",
+		6: string(postScript),
+	}
+	for _, id := range copiedNames {
+		if value := s.names.Name(id); value != "" {
+			names[id] = value
+		}
+	}
+
+	ids := make([]tables.NameID, 0, len(names))
+	for id := range names {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var records, storage []byte
+	for _, id := range ids {
+		var encoded []byte
+		for _, unit := range utf16.Encode([]rune(names[id])) {
+			encoded = binary.BigEndian.AppendUint16(encoded, unit)
+		}
+		for _, v := range []uint16{3, 1, 0x409, uint16(id), uint16(len(encoded)), uint16(len(storage))} {
+			records = binary.BigEndian.AppendUint16(records, v)
+		}
+		storage = append(storage, encoded...)
+	}
+
+	var b []byte
+	b = binary.BigEndian.AppendUint16(b, 0) // format
+	b = binary.BigEndian.AppendUint16(b, uint16(len(ids)))
+	b = binary.BigEndian.AppendUint16(b, uint16(6+len(records)))
+	b = append(b, records...)
+	return append(b, storage...)
+}
+
+// postTable is a version 3 table, which has no glyph names.
+func postTable() []byte {
+	var b []byte
+	b = binary.BigEndian.AppendUint32(b, 0x00030000)
+	b = binary.BigEndian.AppendUint32(b, 0) // italicAngle
+	for _, v := range []int16{-125, 50} {
+		b = binary.BigEndian.AppendUint16(b, uint16(v)) // underlinePosition and underlineThickness
+	}
+	b = binary.BigEndian.AppendUint32(b, 0) // isFixedPitch
+	return append(b, make([]byte, 16)...)
+}
+
+// writeFont lays out the table directory and the tables, and sets the checksum
+// adjustment in head.
+func writeFont(fontTables map[string][]byte) []byte {
+	tags := make([]string, 0, len(fontTables))
+	for tag := range fontTables {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+
+	numTables := uint16(len(tags))
+	searchRange := uint16(1)
+	entrySelector := uint16(0)
+	for searchRange*2 <= numTables {
+		searchRange *= 2
+		entrySelector++
+	}
+
+	var b []byte
+	b = binary.BigEndian.AppendUint32(b, 0x00010000)
+	b = binary.BigEndian.AppendUint16(b, numTables)
+	b = binary.BigEndian.AppendUint16(b, searchRange*16)
+	b = binary.BigEndian.AppendUint16(b, entrySelector)
+	b = binary.BigEndian.AppendUint16(b, numTables*16-searchRange*16)
+
+	offset := len(b) + 16*len(tags)
+	headOffset := 0
+	for _, tag := range tags {
+		data := fontTables[tag]
+		if tag == "head" {
+			headOffset = offset
+		}
+		b = append(b, tag...)
+		b = binary.BigEndian.AppendUint32(b, checksum(data))
+		b = binary.BigEndian.AppendUint32(b, uint32(offset))
+		b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+		offset += (len(data) + 3) &^ 3
+	}
+
+	for _, tag := range tags {
+		b = append(b, fontTables[tag]...)
+		for len(b)%4 != 0 {
+			b = append(b, 0)
+		}
+	}
+
+	binary.BigEndian.PutUint32(b[headOffset+8:], 0xb1b0afba-checksum(b))
+	return b
+}
+
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
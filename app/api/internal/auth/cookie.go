@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+)
+
+// Browsers can ask for tokens to be kept in HttpOnly cookies instead of the response body
+// by sending this header with value "cookie" to any endpoint that issues tokens.
+const authModeHeader = "X-Auth-Mode"
+const authModeCookie = "cookie"
+
+const (
+	accessTokenCookie  = "access_token"
+	refreshTokenCookie = "refresh_token"
+	csrfTokenCookie    = "csrf_token"
+	csrfTokenHeader    = "X-CSRF-Token"
+)
+
+type CookieSessionResponse struct {
+	CSRFToken string `json:"csrf_token"`
+}
+
+// cookieMode reports whether the tokens for this request should be delivered as cookies.
+func cookieMode(c *gin.Context) bool {
+	return strings.EqualFold(c.GetHeader(authModeHeader), authModeCookie) || c.GetBool(authModeCookie)
+}
+
+func secureCookies() bool {
+	return strings.HasPrefix(common.GetConfig().BaseURL, "https://")
+}
+
+// respondWithTokens writes freshly issued tokens either as JSON or, in cookie mode, as
+// HttpOnly cookies together with a readable CSRF cookie for the double-submit check.
+func respondWithTokens(c *gin.Context, tokens *TokenResponse) {
+	if !cookieMode(c) {
+		c.JSON(http.StatusOK, tokens)
+		return
+	}
+
+	csrf, _, err := newOpaqueToken()
+	if err != nil {
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	secure := secureCookies()
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(accessTokenCookie, tokens.AccessToken, int(accessTokenDuration.Seconds()), "/", "", secure, true)
+	c.SetCookie(refreshTokenCookie, tokens.RefreshToken, int(refreshTokenDuration.Seconds()), "/", "", secure, true)
+	c.SetCookie(csrfTokenCookie, csrf, int(refreshTokenDuration.Seconds()), "/", "", secure, false)
+
+	c.JSON(http.StatusOK, CookieSessionResponse{CSRFToken: csrf})
+}
+
+func clearSessionCookies(c *gin.Context) {
+	secure := secureCookies()
+	c.SetSameSite(http.SameSiteStrictMode)
+	for _, name := range []string{accessTokenCookie, refreshTokenCookie, csrfTokenCookie} {
+		c.SetCookie(name, "", -1, "/", "", secure, name != csrfTokenCookie)
+	}
+}
+
+// sessionCookie returns the named token cookie. Requests with unsafe methods must echo
+// the CSRF cookie in the X-CSRF-Token header, otherwise ErrInvalidCSRFToken is returned.
+func sessionCookie(c *gin.Context, name string) (string, error) {
+	token, err := c.Cookie(name)
+	if err != nil || token == "" {
+		return "", common.ErrUnauthorized
+	}
+
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return token, nil
+	}
+
+	expected, err := c.Cookie(csrfTokenCookie)
+	actual := c.GetHeader(csrfTokenHeader)
+	if err != nil || expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) != 1 {
+		return "", common.ErrInvalidCSRFToken
+	}
+
+	return token, nil
+}
+
+func hasSessionCookie(c *gin.Context) bool {
+	token, err := c.Cookie(accessTokenCookie)
+	return err == nil && token != ""
+}
+
+// LogoutHandler
+// @Summary		logout
+// @Description	clear the session cookies set in cookie mode
+// @Tags	Auth
+// @Success	204
+// @Router	/auth/logout [post]
+func LogoutHandler(c *gin.Context) {
+	clearSessionCookies(c)
+	c.Status(http.StatusNoContent)
+}
@@ -1,12 +1,43 @@
 package auth
 
 import (
+	"slices"
+
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type UserCredentials struct {
 	UserID string
 	Roles  []string
+	// Scopes limits what a personal access token may do. It is nil for session tokens,
+	// which are not restricted by scope.
+	Scopes []string
+	// ActorID is the admin behind an impersonation token. AllowWrites reports whether
+	// that token may be used for unsafe methods.
+	ActorID     string
+	AllowWrites bool
+	// ClientID is the OAuth client an access token was issued to.
+	ClientID string
+}
+
+func (credentials *UserCredentials) HasRole(role string) bool {
+	return slices.Contains(credentials.Roles, role)
+}
+
+func (credentials *UserCredentials) Impersonated() bool {
+	return credentials.ActorID != ""
+}
+
+func (credentials *UserCredentials) HasScope(scope string) bool {
+	if credentials.Scopes == nil {
+		return true
+	}
+	return scope != "" && slices.Contains(credentials.Scopes, scope)
 }
 
 func GetUserCredentials(c *gin.Context) *UserCredentials {
@@ -8,12 +8,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/hwangseonu/paperless.dev/internal/common"
 	"github.com/hwangseonu/paperless.dev/internal/database"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type LoginCredentials struct {
 	Username string `json:"username"`
-	Password string `json:"password"`
+	Password string `json:"password" binding:"max=1024"`
 }
 
 type TokenResponse struct {
@@ -21,58 +20,108 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+}
+
 // LoginHandler
 // @Summary		login
-// @Description	get tokens
+// @Description	get tokens, or a challenge token for /auth/login/2fa when two-factor authentication is enabled
 // @Tags	Auth
 // @Accept	json
 // @Produce	json
 // @Param	credentials body	LoginCredentials	true	"login credentials info"
 // @Success	200	{object}	TokenResponse
+// @Success	202	{object}	TwoFactorChallengeResponse
 // @Failure 400 {object}	schema.Error
 // @Failure 401 {object}	schema.Error
-// @Failure 404 {object}	schema.Error
+// @Failure 429 {object}	schema.Error
 // @Failure 500 {object}	schema.Error
 // @Router	/auth/login [post]
 func LoginHandler(c *gin.Context) {
 	var credentials LoginCredentials
 
 	if err := c.ShouldBindJSON(&credentials); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	account := accountKey(credentials.Username)
+	if throttled(c, accountLimiter, account) || throttled(c, ipLimiter, ipKey(c)) {
+		recordLoginFailure(c, nil, credentials.Username, loginMethodPassword, database.LoginFailureThrottled)
 		return
 	}
 
 	user, err := database.NewUserRepository().FindByUsername(credentials.Username)
 
 	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, common.ErrUserNotFound) {
-			status = http.StatusNotFound
+		if !errors.Is(err, common.ErrUserNotFound) {
+			common.AbortWithError(c, err)
+			return
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+
+		// Unknown users get the same work and response as wrong passwords.
+		compareDummyPassword(credentials.Password)
+		accountLimiter.Fail(account)
+		ipLimiter.Fail(ipKey(c))
+		recordLoginFailure(c, nil, credentials.Username, loginMethodPassword, database.LoginFailureUnknownUser)
+		common.AbortWithError(c, common.ErrUnauthorized)
 		return
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(credentials.Password))
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": common.ErrUnauthorized})
+	ok, rehash := VerifyPassword(user.Password, credentials.Password)
+	if !ok {
+		if accountLimiter.Fail(account) {
+			recordLockout(c, user)
+		}
+		ipLimiter.Fail(ipKey(c))
+		recordLoginFailure(c, user, credentials.Username, loginMethodPassword, database.LoginFailureInvalidPassword)
+		common.AbortWithError(c, common.ErrUnauthorized)
 		return
 	}
 
-	access, err1 := GenerateToken(user.ID.Hex(), "access")
-	refresh, err2 := GenerateToken(user.ID.Hex(), "refresh")
+	accountLimiter.Reset(account)
 
-	if err1 != nil || err2 != nil {
-		err = errors.Join(err1, err2)
+	if rehash {
+		upgradePasswordHash(user, credentials.Password)
+	}
+
+	respondWithLogin(c, user, loginMethodPassword)
+}
+
+// respondWithLogin finishes a successful first-factor login. Users with two-factor
+// authentication get a challenge token instead of access and refresh tokens.
+func respondWithLogin(c *gin.Context, user *database.User, method string) {
+	if user.TOTPEnabled {
+		challenge, err := GenerateChallengeToken(user, method)
+		if err != nil {
+			log.Println("an error occurred while generate challenge token", err)
+			common.AbortWithError(c, common.ErrInternal)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		})
+		return
+	}
+
+	completeLogin(c, user, method)
+}
+
+// completeLogin records a successful login and issues access and refresh tokens.
+func completeLogin(c *gin.Context, user *database.User, method string) {
+	tokens, err := issueTokens(user)
+	if err != nil {
 		log.Println("an error occurred while generate tokens", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": common.ErrInvalidToken})
+		common.AbortWithError(c, common.ErrInternal)
 		return
 	}
 
-	c.JSON(http.StatusOK, TokenResponse{
-		AccessToken:  access,
-		RefreshToken: refresh,
-	})
+	recordLoginSuccess(c, user, method)
+	respondWithTokens(c, tokens)
 }
 
 // RefreshHandler
@@ -81,45 +130,70 @@ func LoginHandler(c *gin.Context) {
 // @Tags    Auth
 // @Accept  json
 // @Produce json
-// @Param   Authorization header string true "Bearer {refresh_token}"
+// @Description In cookie mode the refresh token is read from the refresh_token cookie and X-CSRF-Token is required.
+// @Param   Authorization header string false "Bearer {refresh_token}"
+// @Param   X-CSRF-Token header string false "CSRF token, required in cookie mode"
 // @Success 200    {object}   TokenResponse
 // @Failure 401 {object}    schema.Error
+// @Failure 403 {object}    schema.Error
 // @Failure 500 {object}    schema.Error
 // @Router  /auth/refresh [post]
 func RefreshHandler(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-		return
-	}
 
 	tokenString := authHeader
 	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
 		tokenString = authHeader[7:]
 	}
 
+	if authHeader == "" {
+		token, err := sessionCookie(c, refreshTokenCookie)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		tokenString = token
+		c.Set(authModeCookie, true)
+	}
+
 	claims, err := ParseToken(tokenString)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		common.AbortWithError(c, err)
 		return
 	}
 
 	if claims.Subject != "refresh" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		common.AbortWithError(c, common.ErrWrongTokenType)
 		return
 	}
 
-	access, err1 := GenerateToken(claims.UserID, "access")
-	refresh, err2 := GenerateToken(claims.UserID, "refresh")
+	user, err := validateSession(claims)
+	if err != nil {
+		common.AbortWithError(c, common.ErrInvalidToken)
+		return
+	}
 
-	if err1 != nil || err2 != nil {
-		log.Println("an error occurred while generating tokens during refresh:", errors.Join(err1, err2))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": common.ErrInvalidToken})
+	tokens, err := issueTokens(user)
+	if err != nil {
+		log.Println("an error occurred while generating tokens during refresh:", err)
+		common.AbortWithError(c, common.ErrInternal)
 		return
 	}
 
-	c.JSON(http.StatusOK, TokenResponse{
+	respondWithTokens(c, tokens)
+}
+
+func issueTokens(user *database.User) (*TokenResponse, error) {
+	access, err1 := GenerateToken(user, "access")
+	refresh, err2 := GenerateToken(user, "refresh")
+
+	if err1 != nil || err2 != nil {
+		return nil, errors.Join(err1, err2)
+	}
+
+	return &TokenResponse{
 		AccessToken:  access,
 		RefreshToken: refresh,
-	})
+	}, nil
 }
@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxPasswordLength bounds the work done per hash. It is far above any policy
+// minimum, so it only rejects abusive inputs.
+const maxPasswordLength = 1024
+
+var ErrPasswordTooLong = errors.New("password too long")
+var errUnknownHash = errors.New("unknown password hash format")
+
+// PasswordHasher hashes passwords and verifies them against stored hashes.
+// NeedsRehash reports whether a stored hash should be replaced with a fresh one.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(encoded, password string) (bool, error)
+	NeedsRehash(encoded string) bool
+}
+
+// Argon2idHasher produces PHC formatted argon2id hashes.
+type Argon2idHasher struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  int
+	KeyLength   uint32
+}
+
+// BcryptHasher is kept to verify hashes created before argon2id became the default.
+type BcryptHasher struct {
+	Cost int
+}
+
+// MultiHasher hashes with Default and verifies with whichever hasher matches the stored format.
+type MultiHasher struct {
+	Default *Argon2idHasher
+	Legacy  *BcryptHasher
+}
+
+var passwordHasher PasswordHasher = NewPasswordHasher()
+
+// NewPasswordHasher uses the OWASP recommended argon2id parameters.
+func NewPasswordHasher() *MultiHasher {
+	return &MultiHasher{
+		Default: &Argon2idHasher{Memory: 19 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		Legacy:  &BcryptHasher{Cost: bcrypt.DefaultCost},
+	}
+}
+
+func HashPassword(password string) (string, error) {
+	return passwordHasher.Hash(password)
+}
+
+// VerifyPassword checks password against the stored hash and reports whether the hash
+// should be upgraded. An empty hash (e.g. accounts created through social login) never matches.
+func VerifyPassword(encoded, password string) (ok bool, rehash bool) {
+	if encoded == "" {
+		return false, false
+	}
+
+	ok, err := passwordHasher.Verify(encoded, password)
+	if err != nil || !ok {
+		return false, false
+	}
+
+	return true, passwordHasher.NeedsRehash(encoded)
+}
+
+func (h *MultiHasher) Hash(password string) (string, error) {
+	return h.Default.Hash(password)
+}
+
+func (h *MultiHasher) Verify(encoded, password string) (bool, error) {
+	if isBcryptHash(encoded) {
+		return h.Legacy.Verify(encoded, password)
+	}
+	return h.Default.Verify(encoded, password)
+}
+
+func (h *MultiHasher) NeedsRehash(encoded string) bool {
+	return isBcryptHash(encoded) || h.Default.NeedsRehash(encoded)
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	if len(password) > maxPasswordLength {
+		return "", ErrPasswordTooLong
+	}
+
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Verify(encoded, password string) (bool, error) {
+	if len(password) > maxPasswordLength {
+		return false, ErrPasswordTooLong
+	}
+
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory != h.Memory ||
+		params.Iterations != h.Iterations ||
+		params.Parallelism != h.Parallelism ||
+		len(salt) != h.SaltLength ||
+		uint32(len(key)) != h.KeyLength
+}
+
+func decodeArgon2id(encoded string) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, errUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, errUnknownHash
+	}
+
+	params := new(Argon2idHasher)
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return nil, nil, nil, errUnknownHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, errUnknownHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, errUnknownHash
+	}
+
+	return params, salt, key, nil
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	return string(hash), err
+}
+
+// Verify rejects passwords over bcrypt's 72 byte limit instead of silently comparing
+// only their prefix.
+func (h *BcryptHasher) Verify(encoded, password string) (bool, error) {
+	if len(password) > 72 {
+		return false, bcrypt.ErrPasswordTooLong
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.Cost
+}
+
+func isBcryptHash(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// upgradePasswordHash replaces an outdated hash after a successful login. Failures are
+// only logged because the old hash keeps working.
+func upgradePasswordHash(user *database.User, password string) {
+	hash, err := HashPassword(password)
+	if err != nil {
+		log.Println("an error occurred while rehashing password", err)
+		return
+	}
+
+	if err := database.NewUserRepository().SetPasswordHash(user.ID.Hex(), hash); err != nil {
+		log.Println("an error occurred while storing rehashed password", err)
+	}
+}
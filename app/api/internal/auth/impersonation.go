@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const defaultAuditLogLimit = 100
+const maxAuditLogLimit = 500
+
+// validateActor makes sure the admin behind an impersonation token still is one.
+func validateActor(actorID string) error {
+	actor, err := database.NewUserRepository().FindByID(actorID)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(actor.Roles, RoleAdmin) {
+		return common.ErrAccessDenied
+	}
+
+	return nil
+}
+
+// impersonationAllowed limits impersonation tokens to resource routes. Session-only routes
+// manage the account itself (passwords, tokens, 2FA) and stay off-limits, and unsafe
+// methods need a token minted with writes allowed.
+func impersonationAllowed(r route, method string, credentials *UserCredentials) bool {
+	if r.scope == "" {
+		return false
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return credentials.AllowWrites
+	}
+}
+
+func auditImpersonatedRequest(c *gin.Context, credentials *UserCredentials) {
+	writeAuditLog(c, credentials.ActorID, credentials.UserID, &database.AuditLog{
+		Action: database.AuditActionRequest,
+		Method: c.Request.Method,
+		Path:   c.Request.URL.RequestURI(),
+		Status: c.Writer.Status(),
+	})
+}
+
+func writeAuditLog(c *gin.Context, actorID, subjectID string, entry *database.AuditLog) {
+	entry.ActorID, _ = bson.ObjectIDFromHex(actorID)
+	entry.SubjectID, _ = bson.ObjectIDFromHex(subjectID)
+	entry.IP = c.ClientIP()
+	entry.UserAgent = c.Request.UserAgent()
+
+	if _, err := database.NewAuditLogRepository().Create(entry); err != nil {
+		log.Println("an error occurred while writing audit log", err)
+	}
+}
+
+// ImpersonateHandler
+// @Summary		impersonate user
+// @Description	issue a 15 minute access token that acts as another user. Admin only.
+// @Description	The token is read-only unless allowWrites is set, cannot manage the account itself,
+// @Description	and every request made with it is written to the audit log.
+// @Tags	Admin
+// @Accept	json
+// @Produce	json
+// @Param	impersonation body	schema.ImpersonationCreateSchema	true	"target user and reason"
+// @Success	201	{object}	schema.ImpersonationResponseSchema
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 404 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/admin/impersonations [post]
+// @Security     BearerAuth
+func ImpersonateHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	if !credentials.HasRole(RoleAdmin) || credentials.Impersonated() {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	var request schema.ImpersonationCreateSchema
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	if request.UserID == credentials.UserID {
+		common.AbortWithError(c, common.ErrInvalidUserID)
+		return
+	}
+
+	target, err := database.NewUserRepository().FindByID(request.UserID)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	// Admins cannot borrow each other's privileges.
+	if slices.Contains(target.Roles, RoleAdmin) {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	token, expiresAt, err := GenerateImpersonationToken(target, credentials.UserID, request.AllowWrites)
+	if err != nil {
+		log.Println("an error occurred while generate impersonation token", err)
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	writeAuditLog(c, credentials.UserID, target.ID.Hex(), &database.AuditLog{
+		Action: database.AuditActionImpersonationStart,
+		Reason: request.Reason,
+		Status: http.StatusCreated,
+	})
+
+	c.JSON(http.StatusCreated, schema.ImpersonationResponseSchema{
+		AccessToken: token,
+		ExpiresAt:   expiresAt,
+		AllowWrites: request.AllowWrites,
+	})
+}
+
+// ListAuditLogsHandler
+// @Summary		list audit logs
+// @Description	list impersonation audit entries, newest first. Admin only.
+// @Tags	Admin
+// @Produce	json
+// @Param	actorId	query	string	false	"Filter by admin user ID"
+// @Param	subjectId	query	string	false	"Filter by impersonated user ID"
+// @Param	limit	query	int	false	"Maximum number of entries (default 100, max 500)"
+// @Success	200	{object}	object{logs=[]schema.AuditLogResponseSchema}
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/admin/audit-logs [get]
+// @Security     BearerAuth
+func ListAuditLogsHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	if !credentials.HasRole(RoleAdmin) || credentials.Impersonated() {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	limit := defaultAuditLogLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			common.AbortWithError(c, common.ErrInvalidInput)
+			return
+		}
+		limit = min(n, maxAuditLogLimit)
+	}
+
+	logs, err := database.NewAuditLogRepository().FindMany(database.AuditLogFilter{
+		ActorID:   c.Query("actorId"),
+		SubjectID: c.Query("subjectId"),
+		Limit:     int64(limit),
+	})
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	res := make([]*schema.AuditLogResponseSchema, 0)
+	for _, entry := range logs {
+		res = append(res, entry.ResponseSchema())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"logs": res})
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+)
+
+var (
+	accountLimiter   = NewLimiter(3, 10, time.Second, time.Minute, time.Minute*15)
+	ipLimiter        = NewLimiter(20, 100, time.Second, time.Minute, time.Minute*15)
+	twoFactorLimiter = NewLimiter(3, 5, time.Second, time.Minute, time.Minute*15)
+)
+
+var (
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
+
+// compareDummyPassword spends as long as a real password check so that unknown
+// usernames cannot be told apart from wrong passwords by response time.
+func compareDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = HashPassword("paperless.dev")
+	})
+	_, _ = VerifyPassword(dummyHash, password)
+}
+
+func accountKey(username string) string {
+	return "account:" + strings.ToLower(username)
+}
+
+func ipKey(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// throttled aborts with 429 and a Retry-After header when the key may not try again yet.
+func throttled(c *gin.Context, limiter *Limiter, key string) bool {
+	wait, ok := limiter.Allow(key)
+	if ok {
+		return false
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	common.AbortWithError(c, common.ErrTooManyAttempts)
+	return true
+}
+
+// recordLockout stores a lockout event and tells the account owner about it.
+func recordLockout(c *gin.Context, user *database.User) {
+	recordSecurityEvent(c, user, database.SecurityEventLockout, func(event *database.SecurityEvent) error {
+		return notifier.AccountLocked(user, event)
+	})
+}
+
+// recordSecurityEvent stores an event for the user and marks it notified once notify succeeded.
+func recordSecurityEvent(c *gin.Context, user *database.User, eventType string, notify func(*database.SecurityEvent) error) {
+	repository := database.NewSecurityEventRepository()
+
+	event, err := repository.Create(&database.SecurityEvent{
+		UserID:    user.ID,
+		Type:      eventType,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		log.Println("an error occurred while recording security event", err)
+		return
+	}
+
+	if err := notify(event); err != nil {
+		log.Println("an error occurred while sending security notification", err)
+		return
+	}
+
+	if err := repository.MarkNotified(event.ID); err != nil {
+		log.Println("an error occurred while updating security event", err)
+	}
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+const (
+	loginMethodPassword     = "password"
+	loginMethodMagicLink    = "magic-link"
+	loginMethodOIDC         = "oidc"
+	loginMethodPasskey      = "passkey"
+	loginMethodTOTP         = "totp"
+	loginMethodRecoveryCode = "recovery-code"
+)
+
+const defaultLoginHistoryLimit = 50
+const maxLoginHistoryLimit = 200
+
+// deviceFingerprint identifies the browser a request came from. The IP address is left
+// out on purpose because it changes too often to tell devices apart.
+func deviceFingerprint(c *gin.Context) string {
+	sum := sha256.Sum256([]byte(c.Request.UserAgent() + "\n" + c.GetHeader("Accept-Language")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func newLogin(c *gin.Context, user *database.User, identifier, method string) *database.Login {
+	login := &database.Login{
+		Identifier:  identifier,
+		Method:      method,
+		IP:          c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+		Fingerprint: deviceFingerprint(c),
+	}
+	if user != nil {
+		login.UserID = user.ID
+	}
+	return login
+}
+
+// recordLoginSuccess stores the login, updates LastLogin and notifies the user when the
+// login came from a device that has not signed in to the account before.
+func recordLoginSuccess(c *gin.Context, user *database.User, method string) {
+	repository := database.NewLoginRepository()
+	login := newLogin(c, user, user.Username, method)
+	login.Success = true
+
+	known, err := repository.HasSuccessfulLogin(user.ID, login.Fingerprint)
+	if err != nil {
+		log.Println("an error occurred while checking login history", err)
+	} else if !known {
+		// The very first login is not worth a notification.
+		login.NewDevice, err = repository.HasSuccessfulLogin(user.ID, "")
+		if err != nil {
+			log.Println("an error occurred while checking login history", err)
+		}
+	}
+
+	login, err = repository.Create(login)
+	if err != nil {
+		log.Println("an error occurred while recording login", err)
+		return
+	}
+
+	if err := database.NewUserRepository().UpdateLastLogin(user.ID.Hex()); err != nil {
+		log.Println("an error occurred while updating last login", err)
+	}
+
+	if login.NewDevice {
+		recordSecurityEvent(c, user, database.SecurityEventNewDevice, func(*database.SecurityEvent) error {
+			return notifier.NewDeviceLogin(user, login)
+		})
+	}
+}
+
+// recordLoginFailure stores a failed attempt. user is nil when the identifier is unknown.
+func recordLoginFailure(c *gin.Context, user *database.User, identifier, method, reason string) {
+	login := newLogin(c, user, identifier, method)
+	login.Failure = reason
+
+	if _, err := database.NewLoginRepository().Create(login); err != nil {
+		log.Println("an error occurred while recording login", err)
+	}
+}
+
+// ListLoginsHandler
+// @Summary		list login history
+// @Description	list the current user's recent sign-in attempts, newest first
+// @Tags	User
+// @Produce	json
+// @Param	id	path	string	true	"User ID, must be 'me'"
+// @Param	limit	query	int	false	"Maximum number of entries (default 50, max 200)"
+// @Success	200	{object}	object{logins=[]schema.LoginResponseSchema}
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/users/{id}/logins [get]
+// @Security     BearerAuth
+func ListLoginsHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	if c.Param("id") != "me" {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	limit := defaultLoginHistoryLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			common.AbortWithError(c, common.ErrInvalidInput)
+			return
+		}
+		limit = min(n, maxLoginHistoryLimit)
+	}
+
+	logins, err := database.NewLoginRepository().FindManyByUserID(credentials.UserID, int64(limit))
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	res := make([]*schema.LoginResponseSchema, 0)
+	for _, login := range logins {
+		res = append(res, login.ResponseSchema())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"logins": res})
+}
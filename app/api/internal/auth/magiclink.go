@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/mail"
+)
+
+const magicLinkPurpose = "magic-link"
+const magicLinkDuration = time.Minute * 15
+
+// Every request counts against the limiters, so a few links can be sent in a row
+// before further requests are delayed.
+var (
+	magicLinkEmailLimiter = NewLimiter(3, 5, time.Minute, time.Minute*10, time.Hour)
+	magicLinkIPLimiter    = NewLimiter(10, 30, time.Second*10, time.Minute*10, time.Hour)
+)
+
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type VerifyMagicLinkRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// MagicLinkHandler
+// @Summary		request magic link
+// @Description	email a single-use sign-in link if an account with the address exists
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	email body	MagicLinkRequest	true	"account email"
+// @Success	202
+// @Failure 400 {object}	schema.Error
+// @Failure 429 {object}	schema.Error
+// @Router	/auth/magic-link [post]
+func MagicLinkHandler(c *gin.Context) {
+	var request MagicLinkRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	emailKey := "email:" + strings.ToLower(request.Email)
+	if throttled(c, magicLinkIPLimiter, ipKey(c)) || throttled(c, magicLinkEmailLimiter, emailKey) {
+		return
+	}
+	magicLinkIPLimiter.Fail(ipKey(c))
+	magicLinkEmailLimiter.Fail(emailKey)
+
+	// The response is the same whether or not the account exists. The lookup and the
+	// email happen after responding, so response times do not tell either.
+	go requestMagicLink(request.Email)
+
+	c.Status(http.StatusAccepted)
+}
+
+// VerifyMagicLinkHandler
+// @Summary		sign in with magic link
+// @Description	exchange a magic link token for access and refresh tokens.
+// @Description	Responds with 202 and a challenge token when two-factor authentication is enabled.
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	token body	VerifyMagicLinkRequest	true	"magic link token"
+// @Success	200	{object}	TokenResponse
+// @Success	202	{object}	TwoFactorChallengeResponse
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/magic-link/verify [post]
+func VerifyMagicLinkHandler(c *gin.Context) {
+	var request VerifyMagicLinkRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	token, err := consumeOneTimeToken(magicLinkPurpose, request.Token)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	repository := database.NewUserRepository()
+
+	user, err := repository.FindByID(token.UserID.Hex())
+	if err != nil {
+		common.AbortWithError(c, common.ErrInvalidToken)
+		return
+	}
+
+	// Opening the link proves control of the address it was sent to. Links are
+	// revoked when the email changes, so that is still the user's current address.
+	if !user.IsEmailVerified {
+		if verified, err := repository.MarkEmailVerified(user.ID.Hex(), user.Email); err != nil {
+			log.Println("an error occurred while marking email verified", err)
+		} else {
+			user = verified
+		}
+	}
+
+	respondWithLogin(c, user, loginMethodMagicLink)
+}
+
+// RevokeMagicLinks invalidates outstanding magic links, e.g. after the email address changed.
+func RevokeMagicLinks(userID string) error {
+	return database.NewOneTimeTokenRepository().DeleteByUserID(userID, magicLinkPurpose)
+}
+
+// requestMagicLink emails a sign-in link to the account with the address, if any.
+func requestMagicLink(email string) {
+	user, err := database.NewUserRepository().FindByEmail(email)
+	if err != nil {
+		if !errors.Is(err, common.ErrUserNotFound) {
+			log.Println("an error occurred while looking up user for magic link", err)
+		}
+		return
+	}
+
+	if err := sendMagicLinkEmail(user); err != nil {
+		log.Println("an error occurred while sending magic link email", err)
+	}
+}
+
+func sendMagicLinkEmail(user *database.User) error {
+	token, err := issueOneTimeToken(user.ID.Hex(), magicLinkPurpose, magicLinkDuration)
+	if err != nil {
+		return err
+	}
+
+	link := common.GetConfig().BaseURL + "/magic-link?token=" + url.QueryEscape(token)
+
+	return mail.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nOpen the link below to sign in to Paperless.dev.\n\n%s\n\n"+
+				"The link can be used once and expires in 15 minutes. If you did not request this, you can ignore this email.\n",
+			user.Username, link,
+		),
+	})
+}
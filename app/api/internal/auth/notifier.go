@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/mail"
+)
+
+// Notifier tells account owners about security relevant activity on their account.
+type Notifier interface {
+	AccountLocked(user *database.User, event *database.SecurityEvent) error
+	NewDeviceLogin(user *database.User, login *database.Login) error
+}
+
+// MailNotifier sends notifications by email through the configured mailer.
+type MailNotifier struct{}
+
+var notifier Notifier = MailNotifier{}
+
+func GetNotifier() Notifier {
+	return notifier
+}
+
+func SetNotifier(n Notifier) {
+	notifier = n
+}
+
+func (MailNotifier) AccountLocked(user *database.User, event *database.SecurityEvent) error {
+	return mail.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Your account was temporarily locked",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe temporarily locked sign-in to your Paperless.dev account after several failed attempts "+
+				"from %s at %s.\n\nIf this was not you, consider changing your password.\n",
+			user.Username, event.IP, event.CreatedAt.UTC().Format(time.RFC1123),
+		),
+	})
+}
+
+func (MailNotifier) NewDeviceLogin(user *database.User, login *database.Login) error {
+	return mail.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "New sign-in to your account",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nYour Paperless.dev account was signed in to from a new device.\n\n"+
+				"Time: %s\nIP address: %s\nDevice: %s\nMethod: %s\n\n"+
+				"If this was you, there is nothing to do. Otherwise change your password and review your sessions.\n",
+			user.Username, login.CreatedAt.UTC().Format(time.RFC1123), login.IP, login.UserAgent, login.Method,
+		),
+	})
+}
@@ -0,0 +1,402 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const oauthAccessSubject = "oauth-access"
+const oauthCodeDuration = time.Minute * 10
+
+const (
+	oauthClientIDPrefix     = "pdc_"
+	oauthClientSecretPrefix = "pdcs_"
+	oauthRefreshTokenPrefix = "pdr_"
+)
+
+// authorizationRequest is a validated authorization request of the code flow.
+type authorizationRequest struct {
+	client        *database.OAuthClient
+	redirectURI   string
+	state         string
+	scopes        []string
+	codeChallenge string
+}
+
+// authorizationError is a protocol error that is reported back to the client's redirect URI.
+type authorizationError struct {
+	code        string
+	description string
+}
+
+func (err *authorizationError) Error() string {
+	return err.code + ": " + err.description
+}
+
+// validRedirectURI accepts absolute https URIs, and http only for loopback addresses
+// so that native and development clients can receive codes locally.
+func validRedirectURI(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || !u.IsAbs() || u.Fragment != "" || u.Host == "" {
+		return false
+	}
+
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		ip := net.ParseIP(host)
+		return host == "localhost" || (ip != nil && ip.IsLoopback())
+	default:
+		return false
+	}
+}
+
+// parseAuthorizationRequest validates the parameters shared by the consent and decision
+// endpoints. Problems with the client or redirect URI are returned as common errors because
+// they must not be redirected; everything else is an *authorizationError.
+func parseAuthorizationRequest(clientID, redirectURI, scope, state, challenge, challengeMethod string) (*authorizationRequest, error) {
+	client, err := database.NewOAuthClientRepository().FindByClientID(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(client.RedirectURIs, redirectURI) {
+		return nil, common.ErrInvalidRedirectURI
+	}
+
+	request := &authorizationRequest{client: client, redirectURI: redirectURI, state: state}
+
+	if challenge == "" || challengeMethod != "S256" || len(challenge) < 43 || len(challenge) > 128 {
+		return request, &authorizationError{"invalid_request", "PKCE with code_challenge_method S256 is required"}
+	}
+	request.codeChallenge = challenge
+
+	request.scopes = client.Scopes
+	if scope != "" {
+		request.scopes = slices.Compact(slices.Sorted(slices.Values(strings.Fields(scope))))
+	}
+	for _, s := range request.scopes {
+		if !slices.Contains(client.Scopes, s) {
+			return request, &authorizationError{"invalid_scope", "scope " + s + " is not allowed for this client"}
+		}
+	}
+
+	return request, nil
+}
+
+// redirect builds the client callback URI carrying params and the request state.
+func (request *authorizationRequest) redirect(params url.Values) string {
+	if request.state != "" {
+		params.Set("state", request.state)
+	}
+
+	u, _ := url.Parse(request.redirectURI)
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func respondWithAuthorizationError(c *gin.Context, request *authorizationRequest, err error) {
+	var protocolErr *authorizationError
+	if errors.As(err, &protocolErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": common.ErrInvalidInput,
+			"redirectUri": request.redirect(url.Values{
+				"error":             {protocolErr.code},
+				"error_description": {protocolErr.description},
+			}),
+		})
+		return
+	}
+
+	common.AbortWithError(c, err)
+}
+
+// alreadyApproved reports whether the user has an active grant for the client that
+// covers every requested scope.
+func alreadyApproved(userID bson.ObjectID, request *authorizationRequest) bool {
+	grants, err := database.NewOAuthGrantRepository().FindManyActive(userID, request.client.ClientID)
+	if err != nil {
+		log.Println("an error occurred while looking up oauth grants", err)
+		return false
+	}
+
+	for _, grant := range grants {
+		covered := true
+		for _, scope := range request.scopes {
+			covered = covered && slices.Contains(grant.Scopes, scope)
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizeConsentHandler
+// @Summary		describe authorization request
+// @Description	validate an OAuth2 authorization code request and return what the consent screen should show.
+// @Description	Protocol errors respond with 400 and a redirectUri that reports the error to the client.
+// @Tags	OAuth
+// @Produce	json
+// @Param	response_type	query	string	true	"Must be 'code'"
+// @Param	client_id	query	string	true	"Client ID"
+// @Param	redirect_uri	query	string	true	"Registered redirect URI"
+// @Param	scope	query	string	false	"Space separated scopes, defaults to the client's scopes"
+// @Param	state	query	string	false	"Opaque client state"
+// @Param	code_challenge	query	string	true	"PKCE code challenge"
+// @Param	code_challenge_method	query	string	true	"Must be 'S256'"
+// @Success	200	{object}	schema.OAuthConsentSchema
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 404 {object}	schema.Error
+// @Router	/oauth/authorize [get]
+// @Security     BearerAuth
+func AuthorizeConsentHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	request, err := parseAuthorizationRequest(
+		c.Query("client_id"), c.Query("redirect_uri"), c.Query("scope"),
+		c.Query("state"), c.Query("code_challenge"), c.Query("code_challenge_method"),
+	)
+	if err == nil && c.Query("response_type") != "code" {
+		err = &authorizationError{"unsupported_response_type", "only the authorization code flow is supported"}
+	}
+	if err != nil {
+		respondWithAuthorizationError(c, request, err)
+		return
+	}
+
+	userID, _ := bson.ObjectIDFromHex(credentials.UserID)
+
+	c.JSON(http.StatusOK, schema.OAuthConsentSchema{
+		Client: schema.OAuthConsentClientSchema{
+			ClientID: request.client.ClientID,
+			Name:     request.client.Name,
+			Website:  request.client.Website,
+		},
+		Scopes:          request.scopes,
+		RedirectURI:     request.redirectURI,
+		State:           request.state,
+		AlreadyApproved: alreadyApproved(userID, request),
+	})
+}
+
+// AuthorizeDecisionHandler
+// @Summary		approve or deny authorization request
+// @Description	record the user's decision and return the client redirect URI carrying the authorization code or an access_denied error
+// @Tags	OAuth
+// @Accept	json
+// @Produce	json
+// @Param	decision body	schema.OAuthDecisionSchema	true	"authorization request and decision"
+// @Success	200	{object}	schema.OAuthRedirectSchema
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 404 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/oauth/authorize [post]
+// @Security     BearerAuth
+func AuthorizeDecisionHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	var decision schema.OAuthDecisionSchema
+	if err := c.ShouldBindJSON(&decision); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	request, err := parseAuthorizationRequest(
+		decision.ClientID, decision.RedirectURI, decision.Scope,
+		decision.State, decision.CodeChallenge, decision.CodeChallengeMethod,
+	)
+	if err != nil {
+		respondWithAuthorizationError(c, request, err)
+		return
+	}
+
+	if !decision.Approve {
+		c.JSON(http.StatusOK, schema.OAuthRedirectSchema{
+			RedirectURI: request.redirect(url.Values{"error": {"access_denied"}}),
+		})
+		return
+	}
+
+	userID, err := bson.ObjectIDFromHex(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrInvalidUserID)
+		return
+	}
+
+	code, hash, err := newOpaqueToken()
+	if err != nil {
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	_, err = database.NewOAuthCodeRepository().Create(&database.OAuthCode{
+		Hash:          hash,
+		ClientID:      request.client.ClientID,
+		UserID:        userID,
+		RedirectURI:   request.redirectURI,
+		Scopes:        request.scopes,
+		CodeChallenge: request.codeChallenge,
+		ExpiresAt:     time.Now().Add(oauthCodeDuration),
+	})
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schema.OAuthRedirectSchema{
+		RedirectURI: request.redirect(url.Values{"code": {code}}),
+	})
+}
+
+// ListOAuthClientsHandler
+// @Summary		list oauth clients
+// @Description	list the OAuth clients registered by the current user
+// @Tags	OAuth
+// @Produce	json
+// @Success	200	{object}	object{clients=[]schema.OAuthClientResponseSchema}
+// @Failure 401 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/oauth/clients [get]
+// @Security     BearerAuth
+func ListOAuthClientsHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	clients, err := database.NewOAuthClientRepository().FindManyByOwnerID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	res := make([]*schema.OAuthClientResponseSchema, 0)
+	for _, client := range clients {
+		res = append(res, client.ResponseSchema())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"clients": res})
+}
+
+// CreateOAuthClientHandler
+// @Summary		register oauth client
+// @Description	register a third-party application. The client secret of confidential clients is only returned in this response.
+// @Tags	OAuth
+// @Accept	json
+// @Produce	json
+// @Param	client body	schema.OAuthClientCreateSchema	true	"client metadata"
+// @Success	201	{object}	object{client=schema.OAuthClientResponseSchema}
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/oauth/clients [post]
+// @Security     BearerAuth
+func CreateOAuthClientHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	var request schema.OAuthClientCreateSchema
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	for _, uri := range request.RedirectURIs {
+		if !validRedirectURI(uri) {
+			common.AbortWithError(c, common.ErrInvalidRedirectURI)
+			return
+		}
+	}
+
+	for _, scope := range request.Scopes {
+		if !slices.Contains(Scopes, scope) {
+			common.AbortWithError(c, common.ErrInvalidInput)
+			return
+		}
+	}
+
+	ownerID, err := bson.ObjectIDFromHex(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrInvalidUserID)
+		return
+	}
+
+	clientID, _, err1 := newOpaqueToken()
+	secret, _, err2 := newOpaqueToken()
+	if err1 != nil || err2 != nil {
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	client := &database.OAuthClient{
+		ClientID:     oauthClientIDPrefix + clientID[:22],
+		OwnerID:      ownerID,
+		Name:         request.Name,
+		Website:      request.Website,
+		RedirectURIs: slices.Compact(slices.Sorted(slices.Values(request.RedirectURIs))),
+		Scopes:       slices.Compact(slices.Sorted(slices.Values(request.Scopes))),
+	}
+	if request.Confidential {
+		secret = oauthClientSecretPrefix + secret
+		client.SecretHash = hashOpaqueToken(secret)
+	}
+
+	client, err = database.NewOAuthClientRepository().Create(client)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	res := client.ResponseSchema()
+	if request.Confidential {
+		res.ClientSecret = secret
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"client": res})
+}
+
+// DeleteOAuthClientHandler
+// @Summary		delete oauth client
+// @Description	delete one of the current user's OAuth clients and revoke every grant issued to it
+// @Tags	OAuth
+// @Produce	json
+// @Param	id	path	string	true	"Client record ID"
+// @Success	204
+// @Failure 401 {object}	schema.Error
+// @Failure 404 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/oauth/clients/{id} [delete]
+// @Security     BearerAuth
+func DeleteOAuthClientHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	client, err := database.NewOAuthClientRepository().DeleteByID(credentials.UserID, c.Param("id"))
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	if err := database.NewOAuthGrantRepository().RevokeByClientID(client.ClientID); err != nil {
+		log.Println("an error occurred while revoking oauth grants", err)
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,366 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+)
+
+// OAuthTokenResponse is the RFC 6749 access token response.
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// OAuthErrorResponse is the RFC 6749 error response used by the token endpoint.
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// IntrospectionResponse is the RFC 7662 token introspection response.
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+}
+
+func authorizeOAuthAccessToken(claims *Claims) (*UserCredentials, error) {
+	if _, err := validateSession(claims); err != nil {
+		return nil, common.ErrInvalidToken
+	}
+
+	grant, err := database.NewOAuthGrantRepository().FindByID(claims.ID)
+	if err != nil || !grant.Active() || grant.ClientID != claims.ClientID {
+		return nil, common.ErrInvalidToken
+	}
+
+	return &UserCredentials{
+		UserID:   claims.UserID,
+		Roles:    claims.Roles,
+		Scopes:   strings.Fields(claims.Scope),
+		ClientID: claims.ClientID,
+	}, nil
+}
+
+func oauthError(c *gin.Context, status int, code, description string) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, OAuthErrorResponse{Error: code, ErrorDescription: description})
+}
+
+// authenticateClient reads client credentials from HTTP Basic authentication or the form
+// body. Public clients only send client_id.
+func authenticateClient(c *gin.Context) (*database.OAuthClient, bool) {
+	clientID, secret, basic := c.Request.BasicAuth()
+	if !basic {
+		clientID = c.PostForm("client_id")
+		secret = c.PostForm("client_secret")
+	}
+
+	fail := func() (*database.OAuthClient, bool) {
+		if basic {
+			c.Header("WWW-Authenticate", `Basic realm="paperless.dev"`)
+		}
+		oauthError(c, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+		return nil, false
+	}
+
+	if clientID == "" {
+		return fail()
+	}
+
+	client, err := database.NewOAuthClientRepository().FindByClientID(clientID)
+	if err != nil {
+		if !errors.Is(err, common.ErrOAuthClientNotFound) {
+			log.Println("an error occurred while looking up oauth client", err)
+		}
+		return fail()
+	}
+
+	if client.Confidential() {
+		if subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashOpaqueToken(secret))) != 1 {
+			return fail()
+		}
+	} else if secret != "" {
+		return fail()
+	}
+
+	return client, true
+}
+
+func verifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// TokenHandler
+// @Summary		oauth token endpoint
+// @Description	exchange an authorization code (with PKCE) or a refresh token for an access token.
+// @Description	Refresh tokens are rotated on every use.
+// @Tags	OAuth
+// @Accept	x-www-form-urlencoded
+// @Produce	json
+// @Param	grant_type	formData	string	true	"authorization_code or refresh_token"
+// @Param	code	formData	string	false	"Authorization code"
+// @Param	redirect_uri	formData	string	false	"Redirect URI used in the authorization request"
+// @Param	code_verifier	formData	string	false	"PKCE code verifier"
+// @Param	refresh_token	formData	string	false	"Refresh token"
+// @Param	scope	formData	string	false	"Narrower scope for refresh_token"
+// @Param	client_id	formData	string	false	"Client ID, unless HTTP Basic authentication is used"
+// @Param	client_secret	formData	string	false	"Client secret of confidential clients"
+// @Success	200	{object}	OAuthTokenResponse
+// @Failure 400 {object}	OAuthErrorResponse
+// @Failure 401 {object}	OAuthErrorResponse
+// @Router	/oauth/token [post]
+func TokenHandler(c *gin.Context) {
+	client, ok := authenticateClient(c)
+	if !ok {
+		return
+	}
+
+	switch c.PostForm("grant_type") {
+	case "authorization_code":
+		exchangeAuthorizationCode(c, client)
+	case "refresh_token":
+		exchangeRefreshToken(c, client)
+	default:
+		oauthError(c, http.StatusBadRequest, "unsupported_grant_type", "")
+	}
+}
+
+func exchangeAuthorizationCode(c *gin.Context, client *database.OAuthClient) {
+	repository := database.NewOAuthCodeRepository()
+	hash := hashOpaqueToken(c.PostForm("code"))
+
+	code, err := repository.Consume(hash)
+	if err != nil {
+		// A replayed code may have been stolen, so the grant it produced is revoked.
+		if used, err := repository.FindUsed(hash); err == nil && !used.GrantID.IsZero() {
+			if err := database.NewOAuthGrantRepository().Revoke(used.GrantID); err != nil {
+				log.Println("an error occurred while revoking oauth grant", err)
+			}
+		}
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "invalid authorization code")
+		return
+	}
+
+	if code.ClientID != client.ClientID || code.RedirectURI != c.PostForm("redirect_uri") {
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "authorization code was issued to another client or redirect uri")
+		return
+	}
+
+	if !verifyCodeChallenge(c.PostForm("code_verifier"), code.CodeChallenge) {
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "code verifier does not match")
+		return
+	}
+
+	refresh, _, err := newOpaqueToken()
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+	refresh = oauthRefreshTokenPrefix + refresh
+
+	grant, err := database.NewOAuthGrantRepository().Create(&database.OAuthGrant{
+		ClientID:    client.ClientID,
+		UserID:      code.UserID,
+		Scopes:      code.Scopes,
+		RefreshHash: hashOpaqueToken(refresh),
+	})
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+
+	if err := repository.SetGrant(code.ID, grant.ID); err != nil {
+		log.Println("an error occurred while linking oauth code to grant", err)
+	}
+
+	respondWithOAuthTokens(c, grant, refresh)
+}
+
+func exchangeRefreshToken(c *gin.Context, client *database.OAuthClient) {
+	repository := database.NewOAuthGrantRepository()
+	oldHash := hashOpaqueToken(c.PostForm("refresh_token"))
+
+	grant, err := repository.FindByRefreshHash(oldHash)
+	if err != nil || !grant.Active() || grant.ClientID != client.ClientID {
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "invalid refresh token")
+		return
+	}
+
+	if scope := c.PostForm("scope"); scope != "" {
+		scopes := slices.Compact(slices.Sorted(slices.Values(strings.Fields(scope))))
+		for _, s := range scopes {
+			if !slices.Contains(grant.Scopes, s) {
+				oauthError(c, http.StatusBadRequest, "invalid_scope", "scope "+s+" was not granted")
+				return
+			}
+		}
+		grant.Scopes = scopes
+	}
+
+	refresh, _, err := newOpaqueToken()
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+	refresh = oauthRefreshTokenPrefix + refresh
+
+	if err := repository.RotateRefreshHash(grant.ID, oldHash, hashOpaqueToken(refresh)); err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "invalid refresh token")
+		return
+	}
+
+	respondWithOAuthTokens(c, grant, refresh)
+}
+
+func respondWithOAuthTokens(c *gin.Context, grant *database.OAuthGrant, refresh string) {
+	user, err := database.NewUserRepository().FindByID(grant.UserID.Hex())
+	if err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "user no longer exists")
+		return
+	}
+
+	access, err := GenerateOAuthAccessToken(user, grant)
+	if err != nil {
+		log.Println("an error occurred while generate oauth access token", err)
+		oauthError(c, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, OAuthTokenResponse{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(oauthAccessTokenDuration.Seconds()),
+		RefreshToken: refresh,
+		Scope:        strings.Join(grant.Scopes, " "),
+	})
+}
+
+// findGrantForToken resolves an access or refresh token to its grant. Access tokens also
+// return their claims.
+func findGrantForToken(token string) (*database.OAuthGrant, *Claims, error) {
+	repository := database.NewOAuthGrantRepository()
+
+	if strings.HasPrefix(token, oauthRefreshTokenPrefix) {
+		grant, err := repository.FindByRefreshHash(hashOpaqueToken(token))
+		return grant, nil, err
+	}
+
+	claims, err := ParseToken(token)
+	if err != nil || claims.Subject != oauthAccessSubject {
+		return nil, nil, common.ErrInvalidToken
+	}
+
+	grant, err := repository.FindByID(claims.ID)
+	return grant, claims, err
+}
+
+// IntrospectHandler
+// @Summary		oauth token introspection
+// @Description	report whether an access or refresh token issued to the calling client is active (RFC 7662)
+// @Tags	OAuth
+// @Accept	x-www-form-urlencoded
+// @Produce	json
+// @Param	token	formData	string	true	"Token to introspect"
+// @Param	client_id	formData	string	false	"Client ID, unless HTTP Basic authentication is used"
+// @Param	client_secret	formData	string	false	"Client secret of confidential clients"
+// @Success	200	{object}	IntrospectionResponse
+// @Failure 401 {object}	OAuthErrorResponse
+// @Router	/oauth/introspect [post]
+func IntrospectHandler(c *gin.Context) {
+	client, ok := authenticateClient(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+
+	grant, claims, err := findGrantForToken(c.PostForm("token"))
+	if err != nil || !grant.Active() || grant.ClientID != client.ClientID {
+		c.JSON(http.StatusOK, IntrospectionResponse{Active: false})
+		return
+	}
+
+	user, err := database.NewUserRepository().FindByID(grant.UserID.Hex())
+	if err != nil {
+		c.JSON(http.StatusOK, IntrospectionResponse{Active: false})
+		return
+	}
+
+	res := IntrospectionResponse{
+		Active:    true,
+		Scope:     strings.Join(grant.Scopes, " "),
+		ClientID:  grant.ClientID,
+		Username:  user.Username,
+		TokenType: "refresh_token",
+		Sub:       user.ID.Hex(),
+	}
+
+	if claims != nil {
+		if claims.Version != user.TokenVersion {
+			c.JSON(http.StatusOK, IntrospectionResponse{Active: false})
+			return
+		}
+		res.Scope = claims.Scope
+		res.TokenType = "Bearer"
+		res.Exp = claims.ExpiresAt.Unix()
+		res.Iat = claims.IssuedAt.Unix()
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// RevokeHandler
+// @Summary		oauth token revocation
+// @Description	revoke the grant behind an access or refresh token issued to the calling client (RFC 7009).
+// @Description	Responds with 200 even when the token is unknown.
+// @Tags	OAuth
+// @Accept	x-www-form-urlencoded
+// @Param	token	formData	string	true	"Token to revoke"
+// @Param	client_id	formData	string	false	"Client ID, unless HTTP Basic authentication is used"
+// @Param	client_secret	formData	string	false	"Client secret of confidential clients"
+// @Success	200
+// @Failure 401 {object}	OAuthErrorResponse
+// @Router	/oauth/revoke [post]
+func RevokeHandler(c *gin.Context) {
+	client, ok := authenticateClient(c)
+	if !ok {
+		return
+	}
+
+	grant, _, err := findGrantForToken(c.PostForm("token"))
+	if err == nil && grant.ClientID == client.ClientID {
+		if err := database.NewOAuthGrantRepository().Revoke(grant.ID); err != nil {
+			log.Println("an error occurred while revoking oauth grant", err)
+			oauthError(c, http.StatusServiceUnavailable, "temporarily_unavailable", "")
+			return
+		}
+	}
+
+	c.Status(http.StatusOK)
+}
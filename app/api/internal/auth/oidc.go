@@ -0,0 +1,421 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"golang.org/x/oauth2"
+)
+
+const oidcStateCookie = "paperless_oidc_state"
+const oidcStateDuration = time.Minute * 10
+
+// ProviderConfig describes an external identity provider. Providers with an Issuer are
+// configured through OIDC discovery; others (e.g. GitHub) need explicit endpoints and a
+// userinfo URL. TrustEmail marks providers that only ever return verified addresses.
+type ProviderConfig struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer,omitempty"`
+	AuthURL      string   `json:"authURL,omitempty"`
+	TokenURL     string   `json:"tokenURL,omitempty"`
+	UserInfoURL  string   `json:"userInfoURL,omitempty"`
+	ClientID     string   `json:"clientID"`
+	ClientSecret string   `json:"clientSecret"`
+	RedirectURL  string   `json:"redirectURL"`
+	Scopes       []string `json:"scopes,omitempty"`
+	TrustEmail   bool     `json:"trustEmail,omitempty"`
+}
+
+type externalProfile struct {
+	Subject       string
+	Username      string
+	Email         string
+	EmailVerified bool
+}
+
+type oidcProvider struct {
+	config   ProviderConfig
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+type oidcStateClaims struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Cookie   bool   `json:"cookie,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// OIDC signs users in with external identity providers. Providers that use discovery
+// are set up on first use, so an unreachable provider does not stop the server.
+type OIDC struct {
+	configs   map[string]ProviderConfig
+	providers map[string]*oidcProvider
+	mu        sync.Mutex
+}
+
+// ParseProviderConfigs reads the JSON array of provider configs, as given in OIDC_PROVIDERS.
+func ParseProviderConfigs(raw string) ([]ProviderConfig, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var configs []ProviderConfig
+	if err := json.Unmarshal([]byte(raw), &configs); err != nil {
+		return nil, err
+	}
+
+	for _, config := range configs {
+		if config.Name == "" {
+			return nil, errors.New("provider name is required")
+		}
+	}
+	return configs, nil
+}
+
+func NewOIDC(configs []ProviderConfig) *OIDC {
+	o := &OIDC{
+		configs:   make(map[string]ProviderConfig, len(configs)),
+		providers: make(map[string]*oidcProvider),
+	}
+	for _, config := range configs {
+		o.configs[config.Name] = config
+	}
+	return o
+}
+
+// provider returns the named provider, running OIDC discovery on first use.
+func (o *OIDC) provider(ctx context.Context, name string) (*oidcProvider, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if provider, ok := o.providers[name]; ok {
+		return provider, nil
+	}
+
+	config, ok := o.configs[name]
+	if !ok {
+		return nil, common.ErrProviderNotFound
+	}
+
+	provider := &oidcProvider{
+		config: config,
+		oauth2: &oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			RedirectURL:  config.RedirectURL,
+			Scopes:       config.Scopes,
+			Endpoint:     oauth2.Endpoint{AuthURL: config.AuthURL, TokenURL: config.TokenURL},
+		},
+	}
+
+	if config.Issuer != "" {
+		discovered, err := oidc.NewProvider(ctx, config.Issuer)
+		if err != nil {
+			log.Println("an error occurred while discovering identity provider", err)
+			return nil, common.ErrProviderUnavailable
+		}
+
+		provider.oauth2.Endpoint = discovered.Endpoint()
+		provider.verifier = discovered.Verifier(&oidc.Config{ClientID: config.ClientID})
+		if len(provider.oauth2.Scopes) == 0 {
+			provider.oauth2.Scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+		}
+	}
+
+	o.providers[name] = provider
+	return provider, nil
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// LoginHandler
+// @Summary		start external login
+// @Description	redirect to the identity provider using the authorization code flow with PKCE
+// @Tags	Auth
+// @Param	provider	path	string	true	"Provider name"
+// @Param	mode	query	string	false	"Set to 'cookie' to receive the tokens as cookies"
+// @Success	302
+// @Failure 404 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Failure 502 {object}	schema.Error
+// @Router	/auth/oidc/{provider}/login [get]
+func (o *OIDC) LoginHandler(c *gin.Context) {
+	provider, err := o.provider(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	state, err1 := randomString()
+	nonce, err2 := randomString()
+	if err1 != nil || err2 != nil {
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	now := time.Now()
+	cookie, err := jwt.NewWithClaims(jwt.SigningMethodHS256, oidcStateClaims{
+		Provider: provider.config.Name,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+		Cookie:   c.Query("mode") == authModeCookie,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(oidcStateDuration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "paperless.dev",
+			Subject:   "oidc-state",
+		},
+	}).SignedString([]byte(common.GetConfig().JwtSecret))
+	if err != nil {
+		log.Println("an error occurred while signing oidc state", err)
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, cookie, int(oidcStateDuration.Seconds()), "/api/v1/auth/oidc", "", secureCookies(), true)
+
+	options := []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(verifier)}
+	if provider.verifier != nil {
+		options = append(options, oidc.Nonce(nonce))
+	}
+
+	c.Redirect(http.StatusFound, provider.oauth2.AuthCodeURL(state, options...))
+}
+
+// CallbackHandler
+// @Summary		finish external login
+// @Description	exchange the authorization code, then sign in, link or create the matching user
+// @Tags	Auth
+// @Produce	json
+// @Param	provider	path	string	true	"Provider name"
+// @Param	code	query	string	true	"Authorization code"
+// @Param	state	query	string	true	"State"
+// @Success	200	{object}	TokenResponse
+// @Success	202	{object}	TwoFactorChallengeResponse
+// @Failure 401 {object}	schema.Error
+// @Failure 404 {object}	schema.Error
+// @Failure 409 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Failure 502 {object}	schema.Error
+// @Router	/auth/oidc/{provider}/callback [get]
+func (o *OIDC) CallbackHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	provider, err := o.provider(ctx, c.Param("provider"))
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	raw, err := c.Cookie(oidcStateCookie)
+	c.SetCookie(oidcStateCookie, "", -1, "/api/v1/auth/oidc", "", secureCookies(), true)
+	if err != nil {
+		common.AbortWithError(c, common.ErrExternalLogin)
+		return
+	}
+
+	state := new(oidcStateClaims)
+	_, err = jwt.ParseWithClaims(raw, state, func(token *jwt.Token) (interface{}, error) {
+		return []byte(common.GetConfig().JwtSecret), nil
+	})
+	if err != nil || state.Subject != "oidc-state" || state.Provider != provider.config.Name || state.State != c.Query("state") {
+		common.AbortWithError(c, common.ErrExternalLogin)
+		return
+	}
+
+	token, err := provider.oauth2.Exchange(ctx, c.Query("code"), oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		log.Println("an error occurred while exchanging authorization code", err)
+		common.AbortWithError(c, common.ErrExternalLogin)
+		return
+	}
+
+	profile, err := provider.profile(ctx, token, state.Nonce)
+	if err != nil {
+		log.Println("an error occurred while reading external profile", err)
+		common.AbortWithError(c, common.ErrExternalLogin)
+		return
+	}
+
+	user, err := resolveExternalUser(provider.config.Name, profile)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.Set(authModeCookie, state.Cookie)
+	respondWithLogin(c, user, loginMethodOIDC+":"+provider.config.Name)
+}
+
+// profile reads the user's identity from the ID token, or from the userinfo endpoint
+// for plain OAuth2 providers.
+func (p *oidcProvider) profile(ctx context.Context, token *oauth2.Token, nonce string) (*externalProfile, error) {
+	var claims struct {
+		Subject           string `json:"sub"`
+		ID                any    `json:"id"`
+		PreferredUsername string `json:"preferred_username"`
+		Login             string `json:"login"`
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+	}
+
+	if p.verifier != nil {
+		rawIDToken, ok := token.Extra("id_token").(string)
+		if !ok {
+			return nil, errors.New("id_token missing from token response")
+		}
+
+		idToken, err := p.verifier.Verify(ctx, rawIDToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if idToken.Nonce != nonce {
+			return nil, errors.New("id_token nonce mismatch")
+		}
+
+		if err := idToken.Claims(&claims); err != nil {
+			return nil, err
+		}
+	} else {
+		client := p.oauth2.Client(ctx, token)
+		resp, err := client.Get(p.config.UserInfoURL)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("userinfo returned %s", resp.Status)
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+			return nil, err
+		}
+	}
+
+	profile := &externalProfile{
+		Subject:       claims.Subject,
+		Username:      claims.PreferredUsername,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified || (p.config.TrustEmail && claims.Email != ""),
+	}
+
+	if profile.Subject == "" && claims.ID != nil {
+		profile.Subject = fmt.Sprint(claims.ID)
+	}
+	if profile.Username == "" {
+		profile.Username = claims.Login
+	}
+
+	if profile.Subject == "" {
+		return nil, errors.New("external profile has no subject")
+	}
+
+	return profile, nil
+}
+
+// resolveExternalUser finds the user linked to the external identity. Otherwise it links
+// an existing user whose verified email matches, or creates a new one.
+func resolveExternalUser(provider string, profile *externalProfile) (*database.User, error) {
+	repository := database.NewUserRepository()
+	identity := database.Identity{Provider: provider, Subject: profile.Subject}
+
+	user, err := repository.FindByIdentity(provider, profile.Subject)
+	if err == nil {
+		return user, nil
+	} else if !errors.Is(err, common.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if profile.Email != "" {
+		user, err = repository.FindByEmail(profile.Email)
+		if err == nil {
+			// Linking requires both sides to have proven ownership of the address,
+			// otherwise someone could pre-register a victim's email and take over the account.
+			if !profile.EmailVerified || !user.IsEmailVerified {
+				return nil, common.ErrUserConflict
+			}
+
+			if err := repository.AddIdentity(user.ID.Hex(), identity); err != nil {
+				return nil, err
+			}
+			user.Provider = provider
+			return user, nil
+		} else if !errors.Is(err, common.ErrUserNotFound) {
+			return nil, err
+		}
+	}
+
+	username, err := availableUsername(repository, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Insert(&database.User{
+		Username:        username,
+		Email:           profile.Email,
+		Provider:        provider,
+		Identities:      []database.Identity{identity},
+		IsEmailVerified: profile.EmailVerified,
+	})
+}
+
+var usernameSanitizer = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+func availableUsername(repository database.UserRepository, profile *externalProfile) (string, error) {
+	base := profile.Username
+	if base == "" {
+		base, _, _ = strings.Cut(profile.Email, "@")
+	}
+
+	base = usernameSanitizer.ReplaceAllString(strings.ToLower(base), "")
+	if base == "" {
+		base = "user"
+	}
+
+	username := base
+	for i := 0; i < 10; i++ {
+		_, err := repository.FindByUsername(username)
+		if errors.Is(err, common.ErrUserNotFound) {
+			return username, nil
+		} else if err != nil {
+			return "", err
+		}
+
+		suffix, err := randomString()
+		if err != nil {
+			return "", err
+		}
+		username = base + "-" + strings.ToLower(suffix[:6])
+	}
+
+	return "", common.ErrUserConflict
+}
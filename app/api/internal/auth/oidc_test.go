@@ -0,0 +1,395 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"golang.org/x/oauth2"
+)
+
+// testIssuer is a minimal OIDC provider. It hands out a code for every authorization
+// request passed to authorize and checks the PKCE verifier when the code is redeemed.
+type testIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu       sync.Mutex
+	requests map[string]url.Values
+	claims   jwt.MapClaims
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &testIssuer{key: key, requests: make(map[string]url.Values)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"issuer":                                issuer.URL,
+			"authorization_endpoint":                issuer.URL + "/authorize",
+			"token_endpoint":                        issuer.URL + "/token",
+			"userinfo_endpoint":                     issuer.URL + "/userinfo",
+			"jwks_uri":                              issuer.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", issuer.token)
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]any{"id": 42, "login": "octocat", "email": "octocat@example.com"})
+	})
+
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// authorize approves the request encoded in an authorization URL and returns the code.
+func (i *testIssuer) authorize(t *testing.T, location string) string {
+	t.Helper()
+
+	u, err := url.Parse(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(location, i.URL+"/authorize?") {
+		t.Fatalf("redirected to %s, want the authorization endpoint", location)
+	}
+
+	code := "code-" + u.Query().Get("state")
+	i.mu.Lock()
+	i.requests[code] = u.Query()
+	i.mu.Unlock()
+	return code
+}
+
+func (i *testIssuer) token(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+
+	i.mu.Lock()
+	request, ok := i.requests[r.PostForm.Get("code")]
+	delete(i.requests, r.PostForm.Get("code"))
+	claims := i.claims
+	i.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || request.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idClaims := jwt.MapClaims{
+		"iss":   i.URL,
+		"aud":   request.Get("client_id"),
+		"sub":   "subject-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": request.Get("nonce"),
+	}
+	for k, v := range claims {
+		idClaims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idClaims)
+	token.Header["kid"] = "test"
+	idToken, err := token.SignedString(i.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestOIDC(configs ...ProviderConfig) (*OIDC, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	common.GetConfig().JwtSecret = "test-secret"
+
+	o := NewOIDC(configs)
+	engine := gin.New()
+	engine.GET("/api/v1/auth/oidc/:provider/login", o.LoginHandler)
+	engine.GET("/api/v1/auth/oidc/:provider/callback", o.CallbackHandler)
+	return o, engine
+}
+
+// startLogin runs the login handler and returns the redirect location and state cookie.
+func startLogin(t *testing.T, engine *gin.Engine, provider string) (string, *http.Cookie) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/"+provider+"/login", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("login responded %d: %s", w.Code, w.Body)
+	}
+
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == oidcStateCookie {
+			return w.Header().Get("Location"), cookie
+		}
+	}
+	t.Fatal("login did not set the state cookie")
+	return "", nil
+}
+
+func parseStateCookie(t *testing.T, cookie *http.Cookie) *oidcStateClaims {
+	t.Helper()
+
+	state := new(oidcStateClaims)
+	_, err := jwt.ParseWithClaims(cookie.Value, state, func(token *jwt.Token) (interface{}, error) {
+		return []byte(common.GetConfig().JwtSecret), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func TestParseProviderConfigs(t *testing.T) {
+	configs, err := ParseProviderConfigs(`[{"name":"google","issuer":"https://accounts.google.com","clientID":"id"}]`)
+	if err != nil || len(configs) != 1 || configs[0].Name != "google" || configs[0].ClientID != "id" {
+		t.Fatalf("ParseProviderConfigs = %+v, %v", configs, err)
+	}
+
+	if configs, err := ParseProviderConfigs(""); err != nil || configs != nil {
+		t.Errorf("empty config = %+v, %v", configs, err)
+	}
+	if _, err := ParseProviderConfigs(`{"name":"google"}`); err == nil {
+		t.Error("expected an error for a config that is not an array")
+	}
+	if _, err := ParseProviderConfigs(`[{"issuer":"https://accounts.google.com"}]`); err == nil {
+		t.Error("expected an error for a provider without a name")
+	}
+}
+
+func TestOIDCLogin(t *testing.T) {
+	issuer := newTestIssuer(t)
+	_, engine := newTestOIDC(ProviderConfig{
+		Name:        "test",
+		Issuer:      issuer.URL,
+		ClientID:    "client",
+		RedirectURL: "http://localhost/api/v1/auth/oidc/test/callback",
+	})
+
+	location, cookie := startLogin(t, engine, "test")
+	state := parseStateCookie(t, cookie)
+
+	query := mustParseQuery(t, location)
+	if query.Get("client_id") != "client" || query.Get("response_type") != "code" {
+		t.Errorf("unexpected authorization request %s", location)
+	}
+	if query.Get("state") != state.State || query.Get("nonce") != state.Nonce {
+		t.Errorf("state and nonce in %s do not match the cookie", location)
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Errorf("authorization request %s does not use PKCE", location)
+	}
+	if !strings.Contains(query.Get("scope"), "openid") {
+		t.Errorf("scope %q does not request openid", query.Get("scope"))
+	}
+	if !cookie.HttpOnly || cookie.Path != "/api/v1/auth/oidc" {
+		t.Errorf("state cookie %+v is not scoped to the OIDC routes", cookie)
+	}
+}
+
+func TestOIDCUnknownProvider(t *testing.T) {
+	_, engine := newTestOIDC()
+
+	for _, path := range []string{"/api/v1/auth/oidc/missing/login", "/api/v1/auth/oidc/missing/callback"} {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("GET %s responded %d, want 404", path, w.Code)
+		}
+	}
+}
+
+func TestOIDCCallbackRejectsState(t *testing.T) {
+	issuer := newTestIssuer(t)
+	_, engine := newTestOIDC(ProviderConfig{Name: "test", Issuer: issuer.URL, ClientID: "client"})
+
+	location, cookie := startLogin(t, engine, "test")
+	code := issuer.authorize(t, location)
+
+	tests := []struct {
+		name   string
+		state  string
+		cookie *http.Cookie
+	}{
+		{"missing cookie", mustParseQuery(t, location).Get("state"), nil},
+		{"state mismatch", "forged", cookie},
+		{"tampered cookie", mustParseQuery(t, location).Get("state"), &http.Cookie{Name: oidcStateCookie, Value: cookie.Value + "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/api/v1/auth/oidc/test/callback?" + url.Values{"code": {code}, "state": {tt.state}}.Encode()
+			r := httptest.NewRequest(http.MethodGet, target, nil)
+			if tt.cookie != nil {
+				r.AddCookie(tt.cookie)
+			}
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, r)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("callback responded %d, want 401", w.Code)
+			}
+		})
+	}
+}
+
+func TestOIDCProfileFromIDToken(t *testing.T) {
+	issuer := newTestIssuer(t)
+	issuer.claims = jwt.MapClaims{"preferred_username": "jane", "email": "jane@example.com", "email_verified": true}
+
+	o, engine := newTestOIDC(ProviderConfig{Name: "test", Issuer: issuer.URL, ClientID: "client"})
+
+	location, cookie := startLogin(t, engine, "test")
+	state := parseStateCookie(t, cookie)
+	code := issuer.authorize(t, location)
+
+	ctx := context.Background()
+	provider, err := o.provider(ctx, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.oauth2.Exchange(ctx, code, oauth2.VerifierOption("wrong-verifier")); err == nil {
+		t.Fatal("exchange succeeded with the wrong PKCE verifier")
+	}
+
+	code = issuer.authorize(t, location)
+	token, err := provider.oauth2.Exchange(ctx, code, oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.profile(ctx, token, "other-nonce"); err == nil {
+		t.Error("profile accepted an ID token with another nonce")
+	}
+
+	profile, err := provider.profile(ctx, token, state.Nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := externalProfile{Subject: "subject-1", Username: "jane", Email: "jane@example.com", EmailVerified: true}
+	if *profile != want {
+		t.Errorf("profile = %+v, want %+v", *profile, want)
+	}
+}
+
+func TestOIDCProfileRejectsAudience(t *testing.T) {
+	issuer := newTestIssuer(t)
+	issuer.claims = jwt.MapClaims{"aud": "another-client"}
+
+	o, engine := newTestOIDC(ProviderConfig{Name: "test", Issuer: issuer.URL, ClientID: "client"})
+
+	location, cookie := startLogin(t, engine, "test")
+	state := parseStateCookie(t, cookie)
+
+	ctx := context.Background()
+	provider, err := o.provider(ctx, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := provider.oauth2.Exchange(ctx, issuer.authorize(t, location), oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.profile(ctx, token, state.Nonce); err == nil {
+		t.Error("profile accepted an ID token issued to another client")
+	}
+}
+
+func TestOIDCProfileFromUserInfo(t *testing.T) {
+	issuer := newTestIssuer(t)
+
+	o, engine := newTestOIDC(ProviderConfig{
+		Name:        "github",
+		AuthURL:     issuer.URL + "/authorize",
+		TokenURL:    issuer.URL + "/token",
+		UserInfoURL: issuer.URL + "/userinfo",
+		ClientID:    "client",
+		TrustEmail:  true,
+	})
+
+	location, cookie := startLogin(t, engine, "github")
+	state := parseStateCookie(t, cookie)
+	if mustParseQuery(t, location).Get("nonce") != "" {
+		t.Errorf("authorization request %s sends a nonce to a plain OAuth2 provider", location)
+	}
+
+	ctx := context.Background()
+	provider, err := o.provider(ctx, "github")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := provider.oauth2.Exchange(ctx, issuer.authorize(t, location), oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := provider.profile(ctx, token, state.Nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := externalProfile{Subject: "42", Username: "octocat", Email: "octocat@example.com", EmailVerified: true}
+	if *profile != want {
+		t.Errorf("profile = %+v, want %+v", *profile, want)
+	}
+}
+
+func mustParseQuery(t *testing.T, location string) url.Values {
+	t.Helper()
+
+	u, err := url.Parse(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Query()
+}
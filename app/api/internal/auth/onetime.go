@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/database"
+)
+
+const passwordResetPurpose = "password-reset"
+const passwordResetDuration = time.Hour
+
+// newOpaqueToken returns a random URL-safe secret together with the hash that is persisted.
+func newOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashOpaqueToken(token), nil
+}
+
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func issueOneTimeToken(userID, purpose string, duration time.Duration) (string, error) {
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = database.NewOneTimeTokenRepository().Create(userID, purpose, hash, time.Now().Add(duration))
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func findOneTimeToken(purpose, token string) (*database.OneTimeToken, error) {
+	return database.NewOneTimeTokenRepository().Find(purpose, hashOpaqueToken(token))
+}
+
+func consumeOneTimeToken(purpose, token string) (*database.OneTimeToken, error) {
+	return database.NewOneTimeTokenRepository().Consume(purpose, hashOpaqueToken(token))
+}
@@ -0,0 +1,418 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+const passkeyRegistrationPurpose = "passkey-registration"
+const passkeyLoginPurpose = "passkey-login"
+const passkeySessionDuration = time.Minute * 5
+
+// Passkeys registers passkeys and signs users in with them for one relying party.
+type Passkeys struct {
+	rp *webauthn.WebAuthn
+}
+
+func NewPasskeys(rp *webauthn.WebAuthn) *Passkeys {
+	return &Passkeys{rp: rp}
+}
+
+type PasskeyBeginResponse struct {
+	Session string `json:"session"`
+	Options any    `json:"options" swaggertype:"object"`
+}
+
+type PasskeyFinishRequest struct {
+	Session    string          `json:"session" binding:"required"`
+	Name       string          `json:"name" binding:"max=100"`
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// passkeyUser adapts a user and their registered passkeys to webauthn.User.
+// The user handle is the user's ObjectID, so discoverable logins can find the account.
+type passkeyUser struct {
+	user     *database.User
+	passkeys []database.Passkey
+}
+
+func (u *passkeyUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	return u.user.Username
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, passkey := range u.passkeys {
+		credentials = append(credentials, passkey.Credential)
+	}
+	return credentials
+}
+
+// NewRelyingParty configures WebAuthn from config. The relying party ID and allowed
+// origins default to the host and origin of BaseURL.
+func NewRelyingParty(config *common.Config) (*webauthn.WebAuthn, error) {
+	rpID := config.WebAuthnRPID
+	if rpID == "" {
+		base, err := url.Parse(config.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		rpID = base.Hostname()
+	}
+
+	origins := []string{strings.TrimSuffix(config.BaseURL, "/")}
+	if config.WebAuthnRPOrigins != "" {
+		origins = strings.Split(config.WebAuthnRPOrigins, ",")
+		for i := range origins {
+			origins[i] = strings.TrimSpace(origins[i])
+		}
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: "Paperless.dev",
+		RPOrigins:     origins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationPreferred,
+		},
+	})
+}
+
+func loadPasskeyUser(user *database.User) (*passkeyUser, error) {
+	passkeys, err := database.NewPasskeyRepository().FindManyByUserID(user.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	return &passkeyUser{user: user, passkeys: passkeys}, nil
+}
+
+// beginPasskeySession stores the ceremony's session. userID is empty for logins.
+func beginPasskeySession(c *gin.Context, userID, purpose string, data *webauthn.SessionData, options any) {
+	session, err := database.NewWebAuthnSessionRepository().Create(userID, purpose, data, time.Now().Add(passkeySessionDuration))
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PasskeyBeginResponse{Session: session.ID.Hex(), Options: options})
+}
+
+func consumePasskeySession(c *gin.Context, purpose string) (*PasskeyFinishRequest, *database.WebAuthnSession, bool) {
+	var request PasskeyFinishRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return nil, nil, false
+	}
+
+	session, err := database.NewWebAuthnSessionRepository().Consume(purpose, request.Session)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return nil, nil, false
+	}
+
+	return &request, session, true
+}
+
+// BeginRegistrationHandler
+// @Summary		begin passkey registration
+// @Description	create WebAuthn credential creation options for navigator.credentials.create()
+// @Tags	Auth
+// @Produce	json
+// @Success	200	{object}	PasskeyBeginResponse
+// @Failure 401 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/passkeys/register/begin [post]
+// @Security     BearerAuth
+func (p *Passkeys) BeginRegistrationHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	user, err := database.NewUserRepository().FindByID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrUnauthorized)
+		return
+	}
+
+	owner, err := loadPasskeyUser(user)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	exclusions := webauthn.Credentials(owner.WebAuthnCredentials()).CredentialDescriptors()
+	creation, data, err := p.rp.BeginRegistration(owner, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		log.Println("an error occurred while beginning passkey registration", err)
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	beginPasskeySession(c, credentials.UserID, passkeyRegistrationPurpose, data, creation)
+}
+
+// FinishRegistrationHandler
+// @Summary		finish passkey registration
+// @Description	verify the attestation from navigator.credentials.create() and store the passkey
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	passkey body	PasskeyFinishRequest	true	"session, optional name and the public key credential"
+// @Success	201	{object}	object{passkey=schema.PasskeyResponseSchema}
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/passkeys/register/finish [post]
+// @Security     BearerAuth
+func (p *Passkeys) FinishRegistrationHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	request, session, ok := consumePasskeySession(c, passkeyRegistrationPurpose)
+	if !ok {
+		return
+	}
+
+	user, err := database.NewUserRepository().FindByID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrUnauthorized)
+		return
+	}
+
+	owner, err := loadPasskeyUser(user)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	if !bytes.Equal(session.Data.UserID, owner.WebAuthnID()) {
+		common.AbortWithError(c, common.ErrInvalidToken)
+		return
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(request.Credential)
+	if err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	credential, err := p.rp.CreateCredential(owner, session.Data, parsed)
+	if err != nil {
+		log.Println("an error occurred while verifying passkey registration", err)
+		common.AbortWithError(c, common.ErrInvalidPasskey)
+		return
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		name = "Passkey"
+	}
+
+	passkey, err := database.NewPasskeyRepository().Create(&database.Passkey{
+		UserID:     user.ID,
+		Name:       name,
+		Credential: *credential,
+	})
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"passkey": passkey.ResponseSchema()})
+}
+
+// BeginLoginHandler
+// @Summary		begin passkey login
+// @Description	create WebAuthn request options for navigator.credentials.get() with a discoverable credential
+// @Tags	Auth
+// @Produce	json
+// @Success	200	{object}	PasskeyBeginResponse
+// @Failure 429 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/passkeys/login/begin [post]
+func (p *Passkeys) BeginLoginHandler(c *gin.Context) {
+	if throttled(c, ipLimiter, ipKey(c)) {
+		return
+	}
+
+	assertion, data, err := p.rp.BeginDiscoverableLogin()
+	if err != nil {
+		log.Println("an error occurred while beginning passkey login", err)
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	beginPasskeySession(c, "", passkeyLoginPurpose, data, assertion)
+}
+
+// FinishLoginHandler
+// @Summary		finish passkey login
+// @Description	verify the assertion from navigator.credentials.get() and issue tokens.
+// @Description	Responds with 202 and a challenge token when the authenticator did not verify the user
+// @Description	and two-factor authentication is enabled.
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	passkey body	PasskeyFinishRequest	true	"session and the public key credential"
+// @Success	200	{object}	TokenResponse
+// @Success	202	{object}	TwoFactorChallengeResponse
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 429 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/passkeys/login/finish [post]
+func (p *Passkeys) FinishLoginHandler(c *gin.Context) {
+	if throttled(c, ipLimiter, ipKey(c)) {
+		return
+	}
+
+	request, session, ok := consumePasskeySession(c, passkeyLoginPurpose)
+	if !ok {
+		return
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(request.Credential)
+	if err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	var passkey *database.Passkey
+	var account *passkeyUser
+	findUser := func(rawID, userHandle []byte) (webauthn.User, error) {
+		found, err := database.NewPasskeyRepository().FindByCredentialID(rawID)
+		if err != nil {
+			return nil, err
+		}
+		passkey = found
+
+		if !bytes.Equal(passkey.UserID[:], userHandle) {
+			return nil, common.ErrPasskeyNotFound
+		}
+
+		user, err := database.NewUserRepository().FindByID(passkey.UserID.Hex())
+		if err != nil {
+			return nil, err
+		}
+
+		account, err = loadPasskeyUser(user)
+		return account, err
+	}
+
+	_, credential, err := p.rp.ValidatePasskeyLogin(findUser, session.Data, parsed)
+	if err != nil {
+		ipLimiter.Fail(ipKey(c))
+		if account != nil {
+			recordLoginFailure(c, account.user, account.user.Username, loginMethodPasskey, database.LoginFailureInvalidPasskey)
+		}
+		common.AbortWithError(c, common.ErrInvalidPasskey)
+		return
+	}
+
+	// A sign count that did not increase means the private key may have been copied.
+	if credential.Authenticator.CloneWarning {
+		log.Println("passkey sign count did not increase, possible cloned authenticator", passkey.ID.Hex())
+		ipLimiter.Fail(ipKey(c))
+		recordLoginFailure(c, account.user, account.user.Username, loginMethodPasskey, database.LoginFailureInvalidPasskey)
+		common.AbortWithError(c, common.ErrInvalidPasskey)
+		return
+	}
+
+	if err := database.NewPasskeyRepository().UpdateCredential(passkey.ID, credential); err != nil {
+		log.Println("an error occurred while updating passkey", err)
+	}
+
+	user := account.user
+
+	// A user-verified passkey already combines possession with a PIN or biometric,
+	// so it satisfies two-factor authentication on its own.
+	if !credential.Flags.UserVerified {
+		respondWithLogin(c, user, loginMethodPasskey)
+		return
+	}
+
+	completeLogin(c, user, loginMethodPasskey)
+}
+
+// ListPasskeysHandler
+// @Summary		list passkeys
+// @Description	list the current user's registered passkeys
+// @Tags	User
+// @Produce	json
+// @Param	id	path	string	true	"User ID, must be 'me'"
+// @Success	200	{object}	object{passkeys=[]schema.PasskeyResponseSchema}
+// @Failure 401 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/users/{id}/passkeys [get]
+// @Security     BearerAuth
+func ListPasskeysHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	if c.Param("id") != "me" {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	passkeys, err := database.NewPasskeyRepository().FindManyByUserID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	res := make([]*schema.PasskeyResponseSchema, 0)
+	for _, passkey := range passkeys {
+		res = append(res, passkey.ResponseSchema())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"passkeys": res})
+}
+
+// DeletePasskeyHandler
+// @Summary		delete passkey
+// @Description	remove one of the current user's passkeys
+// @Tags	User
+// @Produce	json
+// @Param	id	path	string	true	"User ID, must be 'me'"
+// @Param	passkeyID	path	string	true	"Passkey ID"
+// @Success	204
+// @Failure 401 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 404 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/users/{id}/passkeys/{passkeyID} [delete]
+// @Security     BearerAuth
+func DeletePasskeyHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	if c.Param("id") != "me" {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	err := database.NewPasskeyRepository().DeleteByID(credentials.UserID, c.Param("passkeyID"))
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
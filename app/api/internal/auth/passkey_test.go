@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const testOrigin = "https://paperless.test"
+
+// softAuthenticator is a platform authenticator in software. It signs with a P-256 key,
+// attests with the "none" format and always verifies the user.
+type softAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	rpID      string
+	origin    string
+	signCount uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		t.Fatal(err)
+	}
+	return &softAuthenticator{key: key, id: id, rpID: "paperless.test", origin: testOrigin}
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, kind string, challenge []byte) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{
+		"type":      kind,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    a.origin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// authenticatorData encodes the RP ID hash, the user present and verified flags and the
+// sign count, followed by attested credential data when given.
+func (a *softAuthenticator) authenticatorData(attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	flags := byte(protocol.FlagUserPresent | protocol.FlagUserVerified)
+	if attested != nil {
+		flags |= byte(protocol.FlagAttestedCredentialData)
+	}
+
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attested...)
+}
+
+// create answers navigator.credentials.create() for the challenge.
+func (a *softAuthenticator) create(t *testing.T, challenge []byte) []byte {
+	t.Helper()
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attested := make([]byte, 16) // zero AAGUID
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.id)))
+	attested = append(attested, a.id...)
+	attested = append(attested, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authenticatorData(attested),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(a.clientData(t, "webauthn.create", challenge)),
+		"attestationObject": base64.RawURLEncoding.EncodeToString(attestation),
+	})
+}
+
+// get answers navigator.credentials.get() for the challenge, counting the signature.
+func (a *softAuthenticator) get(t *testing.T, challenge, userHandle []byte) []byte {
+	t.Helper()
+
+	a.signCount++
+	authData := a.authenticatorData(nil)
+	clientData := a.clientData(t, "webauthn.get", challenge)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+		"signature":         base64.RawURLEncoding.EncodeToString(signature),
+		"userHandle":        base64.RawURLEncoding.EncodeToString(userHandle),
+	})
+}
+
+func (a *softAuthenticator) credential(t *testing.T, response map[string]string) []byte {
+	t.Helper()
+
+	id := base64.RawURLEncoding.EncodeToString(a.id)
+	data, err := json.Marshal(map[string]any{"id": id, "rawId": id, "type": "public-key", "response": response})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newTestRelyingParty(t *testing.T) *webauthn.WebAuthn {
+	t.Helper()
+
+	rp, err := NewRelyingParty(&common.Config{BaseURL: testOrigin + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rp
+}
+
+func newTestPasskeyUser() *passkeyUser {
+	return &passkeyUser{user: &database.User{ID: bson.NewObjectID(), Username: "jane"}}
+}
+
+// register runs a registration ceremony and stores the passkey on owner.
+func register(t *testing.T, rp *webauthn.WebAuthn, owner *passkeyUser, authenticator *softAuthenticator) *webauthn.Credential {
+	t.Helper()
+
+	creation, session, err := rp.BeginRegistration(owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(authenticator.create(t, creation.Response.Challenge))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential, err := rp.CreateCredential(owner, *session, parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner.passkeys = append(owner.passkeys, database.Passkey{UserID: owner.user.ID, Credential: *credential})
+	return credential
+}
+
+// login runs a discoverable login ceremony against owner's passkeys.
+func login(t *testing.T, rp *webauthn.WebAuthn, owner *passkeyUser, authenticator *softAuthenticator) (*webauthn.Credential, error) {
+	t.Helper()
+
+	assertion, session, err := rp.BeginDiscoverableLogin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(authenticator.get(t, assertion.Response.Challenge, owner.WebAuthnID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findUser := func(rawID, userHandle []byte) (webauthn.User, error) {
+		return owner, nil
+	}
+	_, credential, err := rp.ValidatePasskeyLogin(findUser, *session, parsed)
+	return credential, err
+}
+
+func TestNewRelyingParty(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	if rp.Config.RPID != "paperless.test" {
+		t.Errorf("RPID = %q, want the host of BASE_URL", rp.Config.RPID)
+	}
+	if len(rp.Config.RPOrigins) != 1 || rp.Config.RPOrigins[0] != testOrigin {
+		t.Errorf("RPOrigins = %v, want the origin of BASE_URL", rp.Config.RPOrigins)
+	}
+
+	rp, err := NewRelyingParty(&common.Config{
+		BaseURL:           testOrigin,
+		WebAuthnRPID:      "example.com",
+		WebAuthnRPOrigins: "https://example.com, https://app.example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rp.Config.RPID != "example.com" {
+		t.Errorf("RPID = %q, want example.com", rp.Config.RPID)
+	}
+	if len(rp.Config.RPOrigins) != 2 || rp.Config.RPOrigins[1] != "https://app.example.com" {
+		t.Errorf("RPOrigins = %q", rp.Config.RPOrigins)
+	}
+}
+
+func TestPasskeyRegistrationAndLogin(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	owner := newTestPasskeyUser()
+	authenticator := newSoftAuthenticator(t)
+
+	registered := register(t, rp, owner, authenticator)
+	if string(registered.ID) != string(authenticator.id) {
+		t.Errorf("registered credential ID %x, want %x", registered.ID, authenticator.id)
+	}
+	if registered.AttestationType != "none" {
+		t.Errorf("attestation type = %q, want none", registered.AttestationType)
+	}
+
+	credential, err := login(t, rp, owner, authenticator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !credential.Flags.UserVerified {
+		t.Error("login did not report user verification")
+	}
+	if credential.Authenticator.CloneWarning {
+		t.Error("clone warning on the first login")
+	}
+	if credential.Authenticator.SignCount != 1 {
+		t.Errorf("sign count = %d, want 1", credential.Authenticator.SignCount)
+	}
+}
+
+func TestPasskeyLoginDetectsClonedAuthenticator(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	owner := newTestPasskeyUser()
+	authenticator := newSoftAuthenticator(t)
+	register(t, rp, owner, authenticator)
+
+	credential, err := login(t, rp, owner, authenticator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner.passkeys[0].Credential = *credential
+
+	// A copy of the key that signs with the same counter again.
+	authenticator.signCount--
+	credential, err = login(t, rp, owner, authenticator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !credential.Authenticator.CloneWarning {
+		t.Error("no clone warning for a sign count that did not increase")
+	}
+}
+
+func TestPasskeyRejectsOtherOrigin(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	owner := newTestPasskeyUser()
+	authenticator := newSoftAuthenticator(t)
+	authenticator.origin = "https://phishing.test"
+
+	creation, session, err := rp.BeginRegistration(owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(authenticator.create(t, creation.Response.Challenge))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rp.CreateCredential(owner, *session, parsed); err == nil {
+		t.Error("registration from another origin was accepted")
+	}
+}
+
+func TestPasskeyLoginRejectsUnknownKey(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	owner := newTestPasskeyUser()
+	register(t, rp, owner, newSoftAuthenticator(t))
+
+	// Same credential ID, different private key.
+	impostor := newSoftAuthenticator(t)
+	impostor.id = owner.passkeys[0].Credential.ID
+	if _, err := login(t, rp, owner, impostor); err == nil {
+		t.Error("login with a signature from another key was accepted")
+	}
+}
+
+func TestPasskeyLoginRejectsReplayedChallenge(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	owner := newTestPasskeyUser()
+	authenticator := newSoftAuthenticator(t)
+	register(t, rp, owner, authenticator)
+
+	assertion, _, err := rp.BeginDiscoverableLogin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, session, err := rp.BeginDiscoverableLogin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The assertion answers the first challenge but is checked against the second session.
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(authenticator.get(t, assertion.Response.Challenge, owner.WebAuthnID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findUser := func(rawID, userHandle []byte) (webauthn.User, error) {
+		return owner, nil
+	}
+	if _, _, err := rp.ValidatePasskeyLogin(findUser, *session, parsed); err == nil {
+		t.Error("assertion for another challenge was accepted")
+	}
+}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/mail"
+)
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" binding:"required,max=1024"`
+	NewPassword     string `json:"newPassword" binding:"required,max=1024"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,max=1024"`
+}
+
+// ChangePasswordHandler
+// @Summary		change password
+// @Description	change the current user's password and revoke all of their sessions
+// @Tags	User
+// @Accept	json
+// @Produce	json
+// @Param	id	path	string	true	"User ID, must be 'me'"
+// @Param	password body	ChangePasswordRequest	true	"current and new password"
+// @Success	204
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/users/{id}/password [post]
+// @Security     BearerAuth
+func ChangePasswordHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	if c.Param("id") != "me" {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	var request ChangePasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	repository := database.NewUserRepository()
+	user, err := repository.FindByID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrUnauthorized)
+		return
+	}
+
+	if ok, _ := VerifyPassword(user.Password, request.CurrentPassword); !ok {
+		common.AbortWithError(c, common.ErrUnauthorized)
+		return
+	}
+
+	if err := CheckPassword(request.NewPassword, user.Username, user.Email); err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	if err := setPassword(user, request.NewPassword); err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPasswordHandler
+// @Summary		request password reset
+// @Description	email a password reset link if an account with the address exists
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	email body	ForgotPasswordRequest	true	"account email"
+// @Success	202
+// @Failure 400 {object}	schema.Error
+// @Router	/auth/password/forgot [post]
+func ForgotPasswordHandler(c *gin.Context) {
+	var request ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	// The response is the same whether or not the account exists. The lookup and the
+	// email happen after responding, so response times do not tell either.
+	go requestPasswordReset(request.Email)
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPasswordHandler
+// @Summary		reset password
+// @Description	set a new password using a reset token and revoke all sessions
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	reset body	ResetPasswordRequest	true	"reset token and new password"
+// @Success	204
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/password/reset [post]
+func ResetPasswordHandler(c *gin.Context) {
+	var request ResetPasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	token, err := findOneTimeToken(passwordResetPurpose, request.Token)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	user, err := database.NewUserRepository().FindByID(token.UserID.Hex())
+	if err != nil {
+		common.AbortWithError(c, common.ErrInvalidToken)
+		return
+	}
+
+	// A rejected password leaves the link usable, so the user can try another one.
+	if err := CheckPassword(request.Password, user.Username, user.Email); err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	if _, err := consumeOneTimeToken(passwordResetPurpose, request.Token); err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	if err := setPassword(user, request.Password); err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	if err := database.NewOneTimeTokenRepository().DeleteByUserID(user.ID.Hex(), passwordResetPurpose); err != nil {
+		log.Println("an error occurred while deleting password reset tokens", err)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// setPassword stores a new password hash. The repository bumps the token version,
+// so every access and refresh token issued before stops working.
+func setPassword(user *database.User, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		log.Println("an error occurred while hashing password", err)
+		return common.ErrInternal
+	}
+
+	return database.NewUserRepository().UpdatePassword(user.ID.Hex(), hash)
+}
+
+// requestPasswordReset emails a reset link to the account with the address, if any.
+func requestPasswordReset(email string) {
+	user, err := database.NewUserRepository().FindByEmail(email)
+	if err != nil {
+		if !errors.Is(err, common.ErrUserNotFound) {
+			log.Println("an error occurred while looking up user for password reset", err)
+		}
+		return
+	}
+
+	if err := sendPasswordResetEmail(user); err != nil {
+		log.Println("an error occurred while sending password reset email", err)
+	}
+}
+
+func sendPasswordResetEmail(user *database.User) error {
+	token, err := issueOneTimeToken(user.ID.Hex(), passwordResetPurpose, passwordResetDuration)
+	if err != nil {
+		return err
+	}
+
+	link := common.GetConfig().BaseURL + "/reset-password?token=" + url.QueryEscape(token)
+
+	return mail.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone requested a password reset for your Paperless.dev account. "+
+				"Open the link below to choose a new password.\n\n%s\n\n"+
+				"The link can be used once and expires in 1 hour. If you did not request this, you can ignore this email.\n",
+			user.Username, link,
+		),
+	})
+}
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const personalAccessTokenPrefix = "pdev_"
+
+const (
+	ScopeResumesRead  = "resumes:read"
+	ScopeResumesWrite = "resumes:write"
+	ScopeUserRead     = "user:read"
+)
+
+var Scopes = []string{ScopeResumesRead, ScopeResumesWrite, ScopeUserRead}
+
+func authorizePersonalAccessToken(token string) (*UserCredentials, error) {
+	repository := database.NewAccessTokenRepository()
+
+	accessToken, err := repository.FindByHash(hashOpaqueToken(token))
+	if err != nil {
+		return nil, common.ErrInvalidToken
+	}
+
+	if accessToken.Expired() {
+		return nil, common.ErrTokenExpired
+	}
+
+	// Deleting an account also deletes its tokens, but a failure there must not
+	// leave them usable.
+	if _, err := database.NewUserRepository().FindByID(accessToken.UserID.Hex()); err != nil {
+		return nil, common.ErrInvalidToken
+	}
+
+	if err := repository.Touch(accessToken.ID); err != nil {
+		log.Println("an error occurred while updating access token usage", err)
+	}
+
+	return &UserCredentials{
+		UserID: accessToken.UserID.Hex(),
+		Roles:  []string{RoleUser},
+		Scopes: accessToken.Scopes,
+	}, nil
+}
+
+// ListAccessTokensHandler
+// @Summary		list personal access tokens
+// @Description	list the current user's personal access tokens without their secrets
+// @Tags	User
+// @Produce	json
+// @Param	id	path	string	true	"User ID, must be 'me'"
+// @Success	200	{object}	object{tokens=[]schema.AccessTokenResponseSchema}
+// @Failure 401 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/users/{id}/tokens [get]
+// @Security     BearerAuth
+func ListAccessTokensHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	if c.Param("id") != "me" {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	tokens, err := database.NewAccessTokenRepository().FindManyByUserID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	res := make([]*schema.AccessTokenResponseSchema, 0)
+	for _, token := range tokens {
+		res = append(res, token.ResponseSchema())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": res})
+}
+
+// CreateAccessTokenHandler
+// @Summary		create personal access token
+// @Description	create a scoped personal access token. The token is only returned in this response.
+// @Tags	User
+// @Accept	json
+// @Produce	json
+// @Param	id	path	string	true	"User ID, must be 'me'"
+// @Param	token body	schema.AccessTokenCreateSchema	true	"name, scopes and optional expiry"
+// @Success	201	{object}	object{token=schema.AccessTokenResponseSchema}
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/users/{id}/tokens [post]
+// @Security     BearerAuth
+func CreateAccessTokenHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	if c.Param("id") != "me" {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	var request schema.AccessTokenCreateSchema
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	for _, scope := range request.Scopes {
+		if !slices.Contains(Scopes, scope) {
+			common.AbortWithError(c, common.ErrInvalidInput)
+			return
+		}
+	}
+
+	if request.ExpiresAt != nil && request.ExpiresAt.Before(time.Now()) {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	userID, err := bson.ObjectIDFromHex(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrInvalidUserID)
+		return
+	}
+
+	secret, _, err := newOpaqueToken()
+	if err != nil {
+		log.Println("an error occurred while generating access token", err)
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+	secret = personalAccessTokenPrefix + secret
+
+	token, err := database.NewAccessTokenRepository().Create(&database.AccessToken{
+		UserID:    userID,
+		Name:      request.Name,
+		Hash:      hashOpaqueToken(secret),
+		Prefix:    secret[:len(personalAccessTokenPrefix)+6],
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(request.Scopes))),
+		ExpiresAt: request.ExpiresAt,
+	})
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	res := token.ResponseSchema()
+	res.Token = secret
+
+	c.JSON(http.StatusCreated, gin.H{"token": res})
+}
+
+// RevokeAccessTokenHandler
+// @Summary		revoke personal access token
+// @Description	delete one of the current user's personal access tokens
+// @Tags	User
+// @Produce	json
+// @Param	id	path	string	true	"User ID, must be 'me'"
+// @Param	tokenID	path	string	true	"Access token ID"
+// @Success	204
+// @Failure 401 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 404 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/users/{id}/tokens/{tokenID} [delete]
+// @Security     BearerAuth
+func RevokeAccessTokenHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	if c.Param("id") != "me" {
+		common.AbortWithError(c, common.ErrAccessDenied)
+		return
+	}
+
+	err := database.NewAccessTokenRepository().DeleteByID(credentials.UserID, c.Param("tokenID"))
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
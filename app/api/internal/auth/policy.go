@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hwangseonu/paperless.dev/internal/breach"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+)
+
+// PasswordPolicy decides whether a new password is acceptable.
+type PasswordPolicy struct {
+	MinLength int
+	MinScore  int
+	Breached  *breach.List
+}
+
+var passwordPolicy = newPasswordPolicy(common.GetConfig())
+
+func newPasswordPolicy(config *common.Config) *PasswordPolicy {
+	policy := &PasswordPolicy{
+		MinLength: config.PasswordMinLength,
+		MinScore:  config.PasswordMinScore,
+	}
+
+	if config.BreachedPasswordsFile != "" {
+		list, err := breach.Load(config.BreachedPasswordsFile)
+		if err != nil {
+			log.Println("breached password check disabled:", err)
+		} else {
+			policy.Breached = list
+		}
+	}
+
+	return policy
+}
+
+// CheckPassword validates a new password for the account identified by username and email.
+// Violations are returned as a *common.ValidationError listing every failed rule.
+func CheckPassword(password, username, email string) error {
+	return passwordPolicy.Check(password, username, email)
+}
+
+func (p *PasswordPolicy) Check(password, username, email string) error {
+	// The other rules are not worth running on a password that can never be hashed.
+	if len(password) > maxPasswordLength {
+		return common.NewValidationError(common.ErrWeakPassword, common.FieldError{
+			Field:   "password",
+			Reason:  "too_long",
+			Message: fmt.Sprintf("must be at most %d bytes long", maxPasswordLength),
+		})
+	}
+
+	var fields []common.FieldError
+	violate := func(reason, message string) {
+		fields = append(fields, common.FieldError{Field: "password", Reason: reason, Message: message})
+	}
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violate("too_short", fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+
+	identities := personalInputs(username, email)
+	lower := strings.ToLower(password)
+	for _, identity := range identities {
+		if strings.Contains(lower, identity) {
+			violate("contains_identity", "must not contain your username or email")
+			break
+		}
+	}
+
+	if score, _ := EstimateStrength(password, identities...); score < p.MinScore {
+		violate("too_weak", "is too easy to guess")
+	}
+
+	if p.Breached != nil && p.Breached.Contains(password) {
+		violate("breached", "has appeared in a data breach and must not be used")
+	}
+
+	if len(fields) > 0 {
+		return common.NewValidationError(common.ErrWeakPassword, fields...)
+	}
+	return nil
+}
+
+// personalInputs returns the parts of the username and email that must not appear in a password.
+func personalInputs(username, email string) []string {
+	local, domain, _ := strings.Cut(email, "@")
+	domain, _, _ = strings.Cut(domain, ".")
+
+	var inputs []string
+	for _, input := range []string{username, local, domain} {
+		if input = strings.ToLower(input); len(input) >= 3 {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
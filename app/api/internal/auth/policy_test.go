@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+)
+
+func TestPasswordPolicyChecksLongPasswordsQuickly(t *testing.T) {
+	policy := &PasswordPolicy{MinLength: 8, MinScore: 3}
+	// Leet and keyboard characters give every segment some pattern work to do.
+	password := strings.Repeat("p4$$w0rd!qwer", maxPasswordLength)[:maxPasswordLength]
+
+	start := time.Now()
+	if err := policy.Check(password, "jane", "jane@example.com"); err != nil {
+		t.Errorf("Check error = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second/2 {
+		t.Errorf("checking a %d byte password took %v", maxPasswordLength, elapsed)
+	}
+}
+
+func TestPasswordPolicyRejectsTooLongPasswords(t *testing.T) {
+	policy := &PasswordPolicy{MinLength: 8, MinScore: 3}
+
+	err := policy.Check(strings.Repeat("a", maxPasswordLength+1), "jane", "jane@example.com")
+	var validationErr *common.ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, common.ErrWeakPassword) {
+		t.Fatalf("Check error = %v, want a weak password error", err)
+	}
+	if len(validationErr.Fields) != 1 || validationErr.Fields[0].Reason != "too_long" {
+		t.Errorf("fields = %+v, want only too_long", validationErr.Fields)
+	}
+}
@@ -9,22 +9,48 @@ import (
 	"github.com/hwangseonu/paperless.dev/internal/common"
 )
 
+type route struct {
+	path     string
+	scope    string
+	optional bool
+}
+
 type Protector struct {
-	protected map[string][]string
+	protected map[string][]route
 }
 
 func NewProtector() *Protector {
-	return &Protector{protected: make(map[string][]string)}
+	return &Protector{protected: make(map[string][]route)}
 }
 
-func (p *Protector) isProtected(method, path string) bool {
-	paths := p.protected[method]
-	return slices.Contains(paths, path)
+func (p *Protector) find(method, path string) (route, bool) {
+	routes := p.protected[method]
+	i := slices.IndexFunc(routes, func(r route) bool { return r.path == path })
+	if i < 0 {
+		return route{}, false
+	}
+	return routes[i], true
 }
 
+// Register protects the path for the given methods. Only session tokens are accepted;
+// personal access tokens need a route registered with RegisterScoped.
 func (p *Protector) Register(path string, methods ...string) {
+	p.register(route{path: path}, methods...)
+}
+
+// RegisterScoped protects the path and also accepts personal access tokens carrying scope.
+func (p *Protector) RegisterScoped(path string, scope string, methods ...string) {
+	p.register(route{path: path, scope: scope}, methods...)
+}
+
+// RegisterOptional authenticates requests that carry credentials but lets anonymous ones through.
+func (p *Protector) RegisterOptional(path string, scope string, methods ...string) {
+	p.register(route{path: path, scope: scope, optional: true}, methods...)
+}
+
+func (p *Protector) register(r route, methods ...string) {
 	for _, method := range methods {
-		p.protected[method] = append(p.protected[method], path)
+		p.protected[method] = append(p.protected[method], r)
 	}
 }
 
@@ -42,47 +68,95 @@ func (p *Protector) RegisterAny(path string) {
 func Authorize(c *gin.Context) (*UserCredentials, error) {
 	authHeader := c.Request.Header.Get("Authorization")
 
+	var token string
 	if authHeader == "" {
-		return nil, common.ErrUnauthorized
-	}
+		// Browsers in cookie mode send the access token as a cookie instead.
+		cookie, err := sessionCookie(c, accessTokenCookie)
+		if err != nil {
+			return nil, err
+		}
+		token = cookie
+	} else {
+		parts := strings.SplitN(authHeader, " ", 2)
+		if !(len(parts) == 2 && parts[0] == "Bearer") || parts[1] == "" {
+			return nil, common.ErrInvalidAuthHeader
+		}
 
-	parts := strings.SplitN(authHeader, " ", 2)
-	if !(len(parts) == 2 && parts[0] == "Bearer") {
-		return nil, common.ErrInvalidInput
+		token = parts[1]
+		if strings.HasPrefix(token, personalAccessTokenPrefix) {
+			return authorizePersonalAccessToken(token)
+		}
 	}
 
-	token := parts[1]
 	claims, err := ParseToken(token)
 
 	if err != nil {
-		return nil, common.ErrInvalidToken
+		return nil, err
+	}
+
+	if claims.Subject == oauthAccessSubject {
+		return authorizeOAuthAccessToken(claims)
 	}
 
 	if claims.Subject != "access" {
+		return nil, common.ErrWrongTokenType
+	}
+
+	if _, err := validateSession(claims); err != nil {
 		return nil, common.ErrInvalidToken
 	}
 
-	return &UserCredentials{
+	credentials := &UserCredentials{
 		UserID: claims.UserID,
 		Roles:  claims.Roles,
-	}, nil
+	}
+
+	if claims.Actor != nil {
+		if err := validateActor(claims.Actor.Subject); err != nil {
+			return nil, common.ErrInvalidToken
+		}
+		credentials.ActorID = claims.Actor.Subject
+		credentials.AllowWrites = claims.AllowWrites
+	}
+
+	return credentials, nil
 
 }
 
 func (p *Protector) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if !p.isProtected(c.Request.Method, c.FullPath()) {
+		r, ok := p.find(c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if r.optional && c.GetHeader("Authorization") == "" && !hasSessionCookie(c) {
 			c.Next()
 			return
 		}
 
 		credentials, err := Authorize(c)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err})
-			c.Abort()
+			common.AbortWithError(c, err)
+			return
+		}
+
+		if !credentials.HasScope(r.scope) {
+			c.Header("WWW-Authenticate", common.BearerChallenge("insufficient_scope", common.ErrInsufficientScope.Message, r.scope))
+			common.AbortWithError(c, common.ErrInsufficientScope)
 			return
 		}
 
+		if credentials.Impersonated() {
+			defer auditImpersonatedRequest(c, credentials)
+
+			if !impersonationAllowed(r, c.Request.Method, credentials) {
+				common.AbortWithError(c, common.ErrImpersonation)
+				return
+			}
+		}
+
 		c.Set("credential", credentials)
 		c.Next()
 	}
@@ -0,0 +1,238 @@
+package auth
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"unicode"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = rankedWords(strings.Fields(commonPasswordsFile))
+
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+	"1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik,9ol.0p;/",
+}
+
+// maxPatternLength is the longest segment matched against the patterns, which keeps the
+// estimate linear in the password length. It covers the longest keyboard row; longer
+// repeats and sequences are matched as several shorter ones.
+const maxPatternLength = 40
+
+var leetSubstitutions = strings.NewReplacer(
+	"4", "a", "@", "a", "8", "b", "(", "c", "3", "e", "6", "g", "1", "i", "!", "i",
+	"|", "l", "0", "o", "$", "s", "5", "s", "7", "t", "+", "t", "2", "z",
+)
+
+func rankedWords(words []string) map[string]int {
+	ranked := make(map[string]int, len(words))
+	for i, word := range words {
+		if _, ok := ranked[word]; !ok {
+			ranked[word] = i + 1
+		}
+	}
+	return ranked
+}
+
+// EstimateStrength scores a password from 0 (trivial) to 4 (strong) in the spirit of zxcvbn.
+// The password is split into the cheapest sequence of known patterns (common passwords,
+// user inputs, keyboard walks, sequences, repeats and years) and brute-forced characters,
+// and the estimated number of guesses is mapped onto the zxcvbn score thresholds.
+func EstimateStrength(password string, userInputs ...string) (int, float64) {
+	runes := []rune(password)
+	n := len(runes)
+	if n == 0 {
+		return 0, 0
+	}
+
+	dictionary := make(map[string]int, len(userInputs))
+	for _, input := range userInputs {
+		if input = strings.ToLower(input); len(input) >= 3 {
+			dictionary[input] = 1
+		}
+	}
+
+	// best[j] is log10 of the fewest guesses needed for the first j runes.
+	best := make([]float64, n+1)
+	for j := 1; j <= n; j++ {
+		best[j] = best[j-1] + math.Log10(cardinality(runes[j-1]))
+
+		for i := max(0, j-maxPatternLength); i < j; i++ {
+			if guesses := patternGuesses(runes[i:j], dictionary); guesses > 0 {
+				best[j] = math.Min(best[j], best[i]+math.Log10(guesses))
+			}
+		}
+	}
+
+	log10Guesses := best[n]
+	switch {
+	case log10Guesses < 3:
+		return 0, math.Pow(10, log10Guesses)
+	case log10Guesses < 6:
+		return 1, math.Pow(10, log10Guesses)
+	case log10Guesses < 8:
+		return 2, math.Pow(10, log10Guesses)
+	case log10Guesses < 10:
+		return 3, math.Pow(10, log10Guesses)
+	default:
+		return 4, math.Pow(10, log10Guesses)
+	}
+}
+
+// patternGuesses returns the guesses needed for the segment if it matches a known pattern, or 0.
+func patternGuesses(segment []rune, userInputs map[string]int) float64 {
+	if len(segment) < 3 {
+		return 0
+	}
+
+	text := string(segment)
+	lower := strings.ToLower(text)
+	guesses := 0.0
+	consider := func(g float64) {
+		if g > 0 && (guesses == 0 || g < guesses) {
+			guesses = g
+		}
+	}
+
+	consider(dictionaryGuesses(text, lower, userInputs))
+	consider(repeatGuesses(segment))
+	consider(sequenceGuesses(segment))
+	consider(keyboardGuesses(lower))
+	consider(yearGuesses(text))
+
+	return guesses
+}
+
+func dictionaryGuesses(text, lower string, userInputs map[string]int) float64 {
+	lookup := func(word string) float64 {
+		if rank, ok := userInputs[word]; ok {
+			return float64(rank)
+		}
+		if rank, ok := commonPasswords[word]; ok {
+			return float64(rank)
+		}
+		return 0
+	}
+
+	variations := 1.0
+	if lower != text {
+		variations = 2
+		if strings.ToUpper(text) != text && !unicode.IsUpper([]rune(text)[0]) {
+			variations = 4
+		}
+	}
+
+	if rank := lookup(lower); rank > 0 {
+		return rank * variations
+	}
+
+	if unleet := leetSubstitutions.Replace(lower); unleet != lower {
+		if rank := lookup(unleet); rank > 0 {
+			return rank * variations * 2
+		}
+	}
+
+	if reversed := reverse(lower); reversed != lower {
+		if rank := lookup(reversed); rank > 0 {
+			return rank * variations * 2
+		}
+	}
+
+	return 0
+}
+
+func repeatGuesses(segment []rune) float64 {
+	for _, r := range segment[1:] {
+		if r != segment[0] {
+			return 0
+		}
+	}
+	return cardinality(segment[0]) * float64(len(segment))
+}
+
+func sequenceGuesses(segment []rune) float64 {
+	delta := segment[1] - segment[0]
+	if delta != 1 && delta != -1 {
+		return 0
+	}
+
+	for i := 2; i < len(segment); i++ {
+		if segment[i]-segment[i-1] != delta {
+			return 0
+		}
+	}
+
+	base := 26.0
+	switch first := unicode.ToLower(segment[0]); {
+	case first == 'a' || first == 'z' || first == '0' || first == '1' || first == '9':
+		base = 4
+	case unicode.IsDigit(first):
+		base = 10
+	}
+
+	if delta < 0 {
+		base *= 2
+	}
+	return base * float64(len(segment))
+}
+
+func keyboardGuesses(lower string) float64 {
+	if len(lower) < 4 {
+		return 0
+	}
+
+	for _, row := range keyboardRows {
+		if strings.Contains(row, lower) || strings.Contains(reverse(row), lower) {
+			return 50 * float64(len(lower))
+		}
+	}
+	return 0
+}
+
+func yearGuesses(text string) float64 {
+	if len(text) != 4 {
+		return 0
+	}
+
+	year := 0
+	for _, r := range text {
+		if !unicode.IsDigit(r) {
+			return 0
+		}
+		year = year*10 + int(r-'0')
+	}
+
+	if year >= 1900 && year <= 2099 {
+		return 200
+	}
+	return 0
+}
+
+func cardinality(r rune) float64 {
+	switch {
+	case unicode.IsDigit(r):
+		return 10
+	case unicode.IsLower(r):
+		return 26
+	case unicode.IsUpper(r):
+		return 26
+	case r < unicode.MaxASCII:
+		return 33
+	default:
+		return 100
+	}
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter tracks failed attempts per key. After a number of free attempts every further
+// failure doubles the delay before the next attempt is allowed, and reaching maxFailures
+// locks the key out for the lockout duration. State is kept in memory.
+type Limiter struct {
+	mu      sync.Mutex
+	entries map[string]*attempts
+
+	freeAttempts int
+	maxFailures  int
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+	lockout      time.Duration
+}
+
+type attempts struct {
+	failures    int
+	nextAllowed time.Time
+	lockedUntil time.Time
+	lastSeen    time.Time
+}
+
+func NewLimiter(freeAttempts, maxFailures int, baseDelay, maxDelay, lockout time.Duration) *Limiter {
+	return &Limiter{
+		entries:      make(map[string]*attempts),
+		freeAttempts: freeAttempts,
+		maxFailures:  maxFailures,
+		baseDelay:    baseDelay,
+		maxDelay:     maxDelay,
+		lockout:      lockout,
+	}
+}
+
+// Allow reports whether an attempt for key may proceed now, and if not, how long to wait.
+func (l *Limiter) Allow(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		return 0, true
+	}
+
+	now := time.Now()
+	if now.Before(entry.lockedUntil) {
+		return entry.lockedUntil.Sub(now), false
+	}
+	if now.Before(entry.nextAllowed) {
+		return entry.nextAllowed.Sub(now), false
+	}
+
+	return 0, true
+}
+
+// Fail records a failed attempt and reports whether it caused a lockout.
+func (l *Limiter) Fail(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	entry, ok := l.entries[key]
+	if !ok || now.After(entry.lastSeen.Add(l.lockout)) {
+		entry = &attempts{}
+		l.entries[key] = entry
+	}
+
+	entry.failures++
+	entry.lastSeen = now
+
+	if entry.failures >= l.maxFailures {
+		entry.failures = 0
+		entry.lockedUntil = now.Add(l.lockout)
+		return true
+	}
+
+	if entry.failures > l.freeAttempts {
+		exponent := float64(entry.failures - l.freeAttempts - 1)
+		delay := time.Duration(float64(l.baseDelay) * math.Pow(2, exponent))
+		entry.nextAllowed = now.Add(min(delay, l.maxDelay))
+	}
+
+	return false
+}
+
+func (l *Limiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.entries, key)
+}
+
+// prune drops entries that have been idle for longer than the lockout window.
+func (l *Limiter) prune(now time.Time) {
+	if len(l.entries) < 10000 {
+		return
+	}
+
+	for key, entry := range l.entries {
+		if now.After(entry.lastSeen.Add(l.lockout)) && now.After(entry.lockedUntil) {
+			delete(l.entries, key)
+		}
+	}
+}
@@ -1,49 +1,153 @@
 package auth
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
 )
 
 type Claims struct {
-	UserID string   `json:"userid"`
-	Roles  []string `json:"roles"`
+	UserID  string   `json:"userid"`
+	Roles   []string `json:"roles,omitempty"`
+	Email   string   `json:"email,omitempty"`
+	Version int      `json:"ver,omitempty"`
+	Methods []string `json:"amr,omitempty"`
+	// Actor is set on impersonation tokens and names the admin acting as UserID.
+	Actor       *ActorClaim `json:"act,omitempty"`
+	AllowWrites bool        `json:"imp_writes,omitempty"`
+	// Scope and ClientID are set on access tokens issued to OAuth clients, whose
+	// grant ID is the token ID.
+	Scope    string `json:"scope,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ActorClaim follows the "act" claim of RFC 8693.
+type ActorClaim struct {
+	Subject string `json:"sub"`
+}
+
 const accessTokenDuration = time.Hour * 24
 const refreshTokenDuration = time.Hour * 24 * 30
+const emailTokenDuration = time.Hour * 24
+const challengeTokenDuration = time.Minute * 5
+const impersonationTokenDuration = time.Minute * 15
+const oauthAccessTokenDuration = time.Hour
 
-func GenerateToken(userID string, subject string) (string, error) {
-	secret := []byte(common.GetConfig().JwtSecret)
-
-	now := &jwt.NumericDate{Time: time.Now()}
-	duration := &jwt.NumericDate{Time: time.Now()}
-	if subject == "access" {
-		duration.Time = duration.Add(accessTokenDuration)
-	} else {
-		duration.Time = duration.Add(refreshTokenDuration)
-	}
+func newClaims(userID string, subject string, duration time.Duration) Claims {
+	now := time.Now()
 
-	claims := Claims{
+	return Claims{
 		UserID: userID,
-		Roles:  []string{"user"},
 		RegisteredClaims: jwt.RegisteredClaims{
 			Audience:  jwt.ClaimStrings{"paperless.dev"},
-			ExpiresAt: duration,
-			IssuedAt:  now,
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "paperless.dev",
-			NotBefore: now,
+			NotBefore: jwt.NewNumericDate(now),
 			Subject:   subject,
 		},
 	}
+}
+
+func signClaims(claims Claims) (string, error) {
+	secret := []byte(common.GetConfig().JwtSecret)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(secret)
+	return token.SignedString(secret)
+}
 
-	return tokenString, err
+func GenerateToken(user *database.User, subject string) (string, error) {
+	duration := refreshTokenDuration
+	if subject == "access" {
+		duration = accessTokenDuration
+	}
+
+	claims := newClaims(user.ID.Hex(), subject, duration)
+	claims.Roles = userRoles(user)
+	claims.Version = user.TokenVersion
+
+	return signClaims(claims)
+}
+
+// GenerateImpersonationToken issues a short-lived access token for target on behalf of
+// the admin actorID. There is no matching refresh token.
+func GenerateImpersonationToken(target *database.User, actorID string, allowWrites bool) (string, time.Time, error) {
+	claims := newClaims(target.ID.Hex(), "access", impersonationTokenDuration)
+	claims.Roles = userRoles(target)
+	claims.Version = target.TokenVersion
+	claims.Actor = &ActorClaim{Subject: actorID}
+	claims.AllowWrites = allowWrites
+
+	token, err := signClaims(claims)
+	return token, claims.ExpiresAt.Time, err
+}
+
+// GenerateOAuthAccessToken issues an access token limited to the scopes of grant.
+func GenerateOAuthAccessToken(user *database.User, grant *database.OAuthGrant) (string, error) {
+	claims := newClaims(user.ID.Hex(), oauthAccessSubject, oauthAccessTokenDuration)
+	claims.Roles = []string{RoleUser}
+	claims.Version = user.TokenVersion
+	claims.Scope = strings.Join(grant.Scopes, " ")
+	claims.ClientID = grant.ClientID
+	claims.ID = grant.ID.Hex()
+
+	return signClaims(claims)
+}
+
+func userRoles(user *database.User) []string {
+	roles := []string{RoleUser}
+	for _, role := range user.Roles {
+		if role != RoleUser {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// GenerateEmailToken issues a token proving ownership of email. It is bound to the
+// address so that it stops working once the user changes their email.
+func GenerateEmailToken(userID string, email string) (string, error) {
+	claims := newClaims(userID, "verify-email", emailTokenDuration)
+	claims.Email = email
+
+	return signClaims(claims)
+}
+
+// GenerateChallengeToken issues a short-lived token that only proves the first step
+// of a two-factor login, done with method. It cannot be used as an access token, and
+// its ID is a one-time token so that it completes a single login.
+func GenerateChallengeToken(user *database.User, method string) (string, error) {
+	id, err := issueOneTimeToken(user.ID.Hex(), twoFactorChallengePurpose, challengeTokenDuration)
+	if err != nil {
+		return "", err
+	}
+
+	claims := newClaims(user.ID.Hex(), "2fa", challengeTokenDuration)
+	claims.ID = id
+	claims.Version = user.TokenVersion
+	claims.Methods = []string{method}
+
+	return signClaims(claims)
+}
+
+// validateSession loads the token owner and rejects tokens issued before
+// the user's sessions were last revoked.
+func validateSession(claims *Claims) (*database.User, error) {
+	user, err := database.NewUserRepository().FindByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Version != user.TokenVersion {
+		return nil, common.ErrInvalidToken
+	}
+
+	return user, nil
 }
 
 func ParseToken(tokenString string) (*Claims, error) {
@@ -52,7 +156,7 @@ func ParseToken(tokenString string) (*Claims, error) {
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, tokenError(err)
 	}
 
 	if !token.Valid {
@@ -61,3 +165,15 @@ func ParseToken(tokenString string) (*Claims, error) {
 
 	return token.Claims.(*Claims), err
 }
+
+// tokenError maps jwt parsing errors onto the codes reported to clients.
+func tokenError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return common.ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return common.ErrMalformedToken
+	default:
+		return common.ErrInvalidToken
+	}
+}
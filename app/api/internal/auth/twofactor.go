@@ -0,0 +1,365 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const totpIssuer = "Paperless.dev"
+const totpPeriod = 30
+const recoveryCodeCount = 10
+const twoFactorChallengePurpose = "2fa-challenge"
+
+type TwoFactorEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QRCode string `json:"qr_code"`
+}
+
+type TwoFactorConfirmRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password     string `json:"password" binding:"max=1024"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+// EnrollTwoFactorHandler
+// @Summary		start two-factor enrollment
+// @Description	generate a TOTP secret with its otpauth:// URI and QR code (PNG data URL)
+// @Tags	Auth
+// @Produce	json
+// @Success	200	{object}	TwoFactorEnrollResponse
+// @Failure 401 {object}	schema.Error
+// @Failure 409 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/2fa/enroll [post]
+// @Security     BearerAuth
+func EnrollTwoFactorHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	repository := database.NewUserRepository()
+	user, err := repository.FindByID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrUnauthorized)
+		return
+	}
+
+	if user.TOTPEnabled {
+		common.AbortWithError(c, common.ErrTwoFactorEnabled)
+		return
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer,
+		AccountName: user.Email,
+	})
+	if err != nil {
+		log.Println("an error occurred while generating totp key", err)
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	img, err := key.Image(256, 256)
+	if err != nil {
+		log.Println("an error occurred while rendering totp qr code", err)
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		log.Println("an error occurred while encoding totp qr code", err)
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	if err := repository.SetPendingTOTP(user.ID.Hex(), key.Secret()); err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TwoFactorEnrollResponse{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	})
+}
+
+// ConfirmTwoFactorHandler
+// @Summary		confirm two-factor enrollment
+// @Description	enable two-factor authentication with a code from the authenticator app and get recovery codes
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	code body	TwoFactorConfirmRequest	true	"current TOTP code"
+// @Success	200	{object}	TwoFactorConfirmResponse
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 409 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/2fa/confirm [post]
+// @Security     BearerAuth
+func ConfirmTwoFactorHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	var request TwoFactorConfirmRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	repository := database.NewUserRepository()
+	user, err := repository.FindByID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrUnauthorized)
+		return
+	}
+
+	if user.TOTPEnabled {
+		common.AbortWithError(c, common.ErrTwoFactorEnabled)
+		return
+	}
+
+	if user.TOTPPending == "" {
+		common.AbortWithError(c, common.ErrTwoFactorNotEnabled)
+		return
+	}
+
+	step, ok := matchTOTP(request.Code, user.TOTPPending, time.Now())
+	if !ok {
+		common.AbortWithError(c, common.ErrInvalidOTP)
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		log.Println("an error occurred while generating recovery codes", err)
+		common.AbortWithError(c, common.ErrInternal)
+		return
+	}
+
+	if err := repository.EnableTOTP(user.ID.Hex(), hashes, step); err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TwoFactorConfirmResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactorHandler
+// @Summary		disable two-factor authentication
+// @Description	disable two-factor authentication after re-authenticating with password and a code. Accounts without a password, such as those created through an identity provider or a magic link, only need the code.
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	credentials body	TwoFactorDisableRequest	true	"password, unless the account has none, and TOTP or recovery code"
+// @Success	204
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 409 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/2fa/disable [post]
+// @Security     BearerAuth
+func DisableTwoFactorHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	var request TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	repository := database.NewUserRepository()
+	user, err := repository.FindByID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrUnauthorized)
+		return
+	}
+
+	if !user.TOTPEnabled {
+		common.AbortWithError(c, common.ErrTwoFactorNotEnabled)
+		return
+	}
+
+	// Accounts created through an identity provider or a magic link have no password
+	// to confirm, so the second factor alone re-authenticates them.
+	if user.Password != "" {
+		if ok, _ := VerifyPassword(user.Password, request.Password); !ok {
+			common.AbortWithError(c, common.ErrUnauthorized)
+			return
+		}
+	}
+
+	if err := verifySecondFactor(user, request.Code, request.RecoveryCode); err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	if err := repository.DisableTOTP(user.ID.Hex()); err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// TwoFactorLoginHandler
+// @Summary		complete two-factor login
+// @Description	exchange a challenge token and a TOTP or recovery code for tokens
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	challenge body	TwoFactorLoginRequest	true	"challenge token and code"
+// @Success	200	{object}	TokenResponse
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 429 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/login/2fa [post]
+func TwoFactorLoginHandler(c *gin.Context) {
+	var request TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	claims, err := ParseToken(request.ChallengeToken)
+	if err != nil || claims.Subject != "2fa" {
+		common.AbortWithError(c, common.ErrInvalidToken)
+		return
+	}
+
+	user, err := validateSession(claims)
+	if err != nil || !user.TOTPEnabled {
+		common.AbortWithError(c, common.ErrInvalidToken)
+		return
+	}
+
+	method := loginMethodPassword
+	if len(claims.Methods) > 0 {
+		method = claims.Methods[0]
+	}
+	if request.RecoveryCode != "" {
+		method += "+" + loginMethodRecoveryCode
+	} else {
+		method += "+" + loginMethodTOTP
+	}
+
+	key := "2fa:" + user.ID.Hex()
+	if throttled(c, twoFactorLimiter, key) {
+		recordLoginFailure(c, user, user.Username, method, database.LoginFailureThrottled)
+		return
+	}
+
+	if err := verifySecondFactor(user, request.Code, request.RecoveryCode); err != nil {
+		if twoFactorLimiter.Fail(key) {
+			recordLockout(c, user)
+		}
+		recordLoginFailure(c, user, user.Username, method, database.LoginFailureInvalidCode)
+		common.AbortWithError(c, err)
+		return
+	}
+
+	// The challenge is only burned once the code was accepted, so a mistyped code
+	// does not send the user back to the password step.
+	if _, err := consumeOneTimeToken(twoFactorChallengePurpose, claims.ID); err != nil {
+		common.AbortWithError(c, common.ErrInvalidToken)
+		return
+	}
+
+	twoFactorLimiter.Reset(key)
+	completeLogin(c, user, method)
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused recovery code.
+// Both are burned on use.
+func verifySecondFactor(user *database.User, code, recoveryCode string) error {
+	if code != "" {
+		step, ok := matchTOTP(code, user.TOTPSecret, time.Now())
+		if !ok {
+			return common.ErrInvalidOTP
+		}
+		err := database.NewUserRepository().AcceptTOTPStep(user.ID.Hex(), step)
+		if errors.Is(err, common.ErrInvalidToken) {
+			return common.ErrInvalidOTP
+		}
+		return err
+	}
+
+	if recoveryCode != "" {
+		hash := hashOpaqueToken(normalizeRecoveryCode(recoveryCode))
+		err := database.NewUserRepository().ConsumeRecoveryCode(user.ID.Hex(), hash)
+		if errors.Is(err, common.ErrInvalidToken) {
+			return common.ErrInvalidOTP
+		}
+		return err
+	}
+
+	return common.ErrInvalidOTP
+}
+
+// matchTOTP reports whether code is valid at now, allowing one step of clock skew
+// either way, and returns the time step it belongs to.
+func matchTOTP(code, secret string, now time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	for _, skew := range []int{0, -1, 1} {
+		t := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+		if ok, _ := totp.ValidateCustom(code, secret, t, opts); ok {
+			return t.Unix() / totpPeriod, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCodes returns recovery codes formatted for display and their hashes for storage.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	for i := range codes {
+		buf := make([]byte, 6)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(encoding.EncodeToString(buf))
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hashOpaqueToken(code)
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ToLower(code)
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/mail"
+)
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// SendVerificationEmail mails a fresh verification link to the user's current address.
+func SendVerificationEmail(user *database.User) error {
+	token, err := GenerateEmailToken(user.ID.Hex(), user.Email)
+	if err != nil {
+		return err
+	}
+
+	link := common.GetConfig().BaseURL + "/verify-email?token=" + url.QueryEscape(token)
+
+	return mail.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below.\n\n%s\n\n"+
+				"The link expires in 24 hours. If you did not sign up for Paperless.dev, you can ignore this email.\n",
+			user.Username, link,
+		),
+	})
+}
+
+// EnsureEmailVerified returns ErrEmailNotVerified unless the user has confirmed their email.
+// Features that expose user content to others should call this before enabling it.
+func EnsureEmailVerified(userID string) error {
+	user, err := database.NewUserRepository().FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsEmailVerified {
+		return common.ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+// VerifyEmailHandler
+// @Summary		verify email
+// @Description	mark the email address in the verification token as verified
+// @Tags	Auth
+// @Accept	json
+// @Produce	json
+// @Param	token body	VerifyEmailRequest	true	"verification token"
+// @Success	200	{object}	object{user=schema.UserResponseSchema}
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/verify-email [post]
+func VerifyEmailHandler(c *gin.Context) {
+	var request VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.AbortWithError(c, common.ErrInvalidInput)
+		return
+	}
+
+	claims, err := ParseToken(request.Token)
+	if err != nil || claims.Subject != "verify-email" {
+		common.AbortWithError(c, common.ErrInvalidToken)
+		return
+	}
+
+	user, err := database.NewUserRepository().MarkEmailVerified(claims.UserID, claims.Email)
+	if err != nil {
+		if errors.Is(err, common.ErrUserNotFound) {
+			common.AbortWithError(c, common.ErrInvalidToken)
+			return
+		}
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user.ResponseSchema()})
+}
+
+// ResendVerificationHandler
+// @Summary		resend verification email
+// @Description	send a new verification link to the current user's email
+// @Tags	Auth
+// @Produce	json
+// @Success	202
+// @Failure 401 {object}	schema.Error
+// @Failure 404 {object}	schema.Error
+// @Failure 409 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/auth/verify-email/resend [post]
+// @Security     BearerAuth
+func ResendVerificationHandler(c *gin.Context) {
+	credentials := MustGetUserCredentials(c)
+
+	user, err := database.NewUserRepository().FindByID(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrUserNotFound)
+		return
+	}
+
+	if user.IsEmailVerified {
+		common.AbortWithError(c, common.ErrEmailAlreadyVerified)
+		return
+	}
+
+	if err := SendVerificationEmail(user); err != nil {
+		log.Println("an error occurred while sending verification email", err)
+		common.AbortWithError(c, common.ErrMail)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
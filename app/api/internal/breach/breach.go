@@ -0,0 +1,107 @@
+// Package breach checks passwords against a locally stored list of breached passwords.
+//
+// The list is a compact binary file: an 8 byte header ("PDBL", a version byte, the prefix
+// length in bytes and two reserved bytes) followed by the sorted, de-duplicated leading
+// bytes of each password's SHA-1 digest. Keeping only a prefix of every digest makes the
+// file a fraction of the size of the full hash list while keeping false positives negligible.
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"sort"
+)
+
+const (
+	magic         = "PDBL"
+	version       = 1
+	headerLength  = 8
+	DefaultPrefix = 8
+)
+
+var ErrInvalidFormat = errors.New("breach: invalid list format")
+
+type List struct {
+	prefixLength int
+	entries      []byte
+}
+
+func Load(path string) (*List, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*List, error) {
+	if len(data) < headerLength || string(data[:4]) != magic || data[4] != version {
+		return nil, ErrInvalidFormat
+	}
+
+	prefixLength := int(data[5])
+	if prefixLength < 4 || prefixLength > sha1.Size || (len(data)-headerLength)%prefixLength != 0 {
+		return nil, ErrInvalidFormat
+	}
+
+	return &List{prefixLength: prefixLength, entries: data[headerLength:]}, nil
+}
+
+func (l *List) Len() int {
+	return len(l.entries) / l.prefixLength
+}
+
+func (l *List) Contains(password string) bool {
+	return l.ContainsHash(sha1.Sum([]byte(password)))
+}
+
+func (l *List) ContainsHash(sum [sha1.Size]byte) bool {
+	prefix := sum[:l.prefixLength]
+	n := l.Len()
+
+	i := sort.Search(n, func(i int) bool {
+		return bytes.Compare(l.entry(i), prefix) >= 0
+	})
+
+	return i < n && bytes.Equal(l.entry(i), prefix)
+}
+
+func (l *List) entry(i int) []byte {
+	return l.entries[i*l.prefixLength : (i+1)*l.prefixLength]
+}
+
+// Write stores the digests as a list keeping prefixLength bytes of each.
+func Write(w io.Writer, prefixLength int, digests [][]byte) error {
+	if prefixLength < 4 || prefixLength > sha1.Size {
+		return ErrInvalidFormat
+	}
+
+	prefixes := make([][]byte, 0, len(digests))
+	for _, digest := range digests {
+		if len(digest) < prefixLength {
+			return ErrInvalidFormat
+		}
+		prefixes = append(prefixes, digest[:prefixLength])
+	}
+
+	slices.SortFunc(prefixes, bytes.Compare)
+	prefixes = slices.CompactFunc(prefixes, bytes.Equal)
+
+	buf := bufio.NewWriter(w)
+	if _, err := buf.Write([]byte{'P', 'D', 'B', 'L', version, byte(prefixLength), 0, 0}); err != nil {
+		return err
+	}
+
+	for _, prefix := range prefixes {
+		if _, err := buf.Write(prefix); err != nil {
+			return err
+		}
+	}
+
+	return buf.Flush()
+}
@@ -1,19 +1,77 @@
 package common
 
-import "os"
+import (
+	"os"
+	"strconv"
+)
 
 var conf *Config
 
 type Config struct {
 	MongoURI  string
 	JwtSecret string
+	BaseURL   string
+
+	TrustedProxies string
+
+	Mailer       string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	OIDCProviders string
+
+	WebAuthnRPID      string
+	WebAuthnRPOrigins string
+
+	PasswordMinLength     int
+	PasswordMinScore      int
+	BreachedPasswordsFile string
 }
 
 func init() {
 	conf = &Config{
 		MongoURI:  os.Getenv("MONGO_URI"),
 		JwtSecret: os.Getenv("JWT_SECRET"),
+		BaseURL:   getEnv("BASE_URL", "http://localhost:8000"),
+
+		TrustedProxies: os.Getenv("TRUSTED_PROXIES"),
+
+		Mailer:       getEnv("MAILER", "log"),
+		MailFrom:     getEnv("MAIL_FROM", "no-reply@paperless.dev"),
+		MailDir:      getEnv("MAIL_DIR", "mail"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+
+		OIDCProviders: os.Getenv("OIDC_PROVIDERS"),
+
+		WebAuthnRPID:      os.Getenv("WEBAUTHN_RP_ID"),
+		WebAuthnRPOrigins: os.Getenv("WEBAUTHN_RP_ORIGINS"),
+
+		PasswordMinLength:     getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordMinScore:      getEnvInt("PASSWORD_MIN_SCORE", 2),
+		BreachedPasswordsFile: os.Getenv("BREACHED_PASSWORDS_FILE"),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
 	}
+	return value
 }
 
 func GetConfig() *Config {
@@ -2,6 +2,7 @@ package common
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 
@@ -9,33 +10,117 @@ import (
 )
 
 const (
-	CodeDatabaseError = 1001
-	CodeInvalidInput  = 1002
-	CodeUnauthorized  = 1003
-	CodeAccessDenied  = 1004
+	CodeDatabaseError     = 1001
+	CodeInvalidInput      = 1002
+	CodeUnauthorized      = 1003
+	CodeAccessDenied      = 1004
+	CodeMailError         = 1005
+	CodeInsufficientScope = 1006
+	CodeTooManyAttempts   = 1007
+	CodeWeakPassword      = 1008
+	CodeInvalidCSRFToken  = 1009
+	CodeImpersonation     = 1010
+	CodeInvalidAuthHeader = 1011
+	CodeInternal          = 1012
 
 	CodeUserNotFound  = 2001
 	CodeInvalidUserID = 2002
 	CodeInvalidToken  = 2003
 	CodeUserConflict  = 2004
 
+	CodeEmailNotVerified     = 2005
+	CodeEmailAlreadyVerified = 2006
+
+	CodeTwoFactorEnabled    = 2007
+	CodeTwoFactorNotEnabled = 2008
+	CodeInvalidOTP          = 2009
+
+	CodeProviderNotFound = 2010
+	CodeExternalLogin    = 2011
+
+	CodeAccessTokenNotFound = 2012
+
+	CodePasskeyNotFound = 2013
+	CodeInvalidPasskey  = 2014
+
+	CodeOAuthClientNotFound = 2015
+	CodeInvalidRedirectURI  = 2016
+
+	CodeTokenExpired   = 2017
+	CodeMalformedToken = 2018
+	CodeWrongTokenType = 2019
+
+	CodeProviderUnavailable = 2020
+
 	CodeResumeNotFound  = 3001
 	CodeInvalidResumeID = 3002
+	CodeExportFailed    = 3003
+
+	CodeInvalidTemplate  = 3004
+	CodeTemplateNotFound = 3005
+	CodeSlugConflict     = 3006
+
+	CodeUnsupportedFormat = 3007
+	CodeInvalidImport     = 3008
+
+	CodeSandboxBusy = 3009
 )
 
 var (
-	ErrDatabase     = &Error{"database error", CodeDatabaseError}
-	ErrInvalidInput = &Error{"invalid input", CodeInvalidInput}
-	ErrUnauthorized = &Error{"unauthorized", CodeUnauthorized}
-	ErrAccessDenied = &Error{"access denied", CodeAccessDenied}
+	ErrDatabase          = &Error{"database error", CodeDatabaseError}
+	ErrInvalidInput      = &Error{"invalid input", CodeInvalidInput}
+	ErrUnauthorized      = &Error{"unauthorized", CodeUnauthorized}
+	ErrAccessDenied      = &Error{"access denied", CodeAccessDenied}
+	ErrMail              = &Error{"failed to send email", CodeMailError}
+	ErrInsufficientScope = &Error{"insufficient scope", CodeInsufficientScope}
+	ErrTooManyAttempts   = &Error{"too many attempts", CodeTooManyAttempts}
+	ErrWeakPassword      = &Error{"password does not meet the password policy", CodeWeakPassword}
+	ErrInvalidCSRFToken  = &Error{"invalid csrf token", CodeInvalidCSRFToken}
+	ErrImpersonation     = &Error{"not allowed while impersonating", CodeImpersonation}
+	ErrInvalidAuthHeader = &Error{"malformed authorization header", CodeInvalidAuthHeader}
+	ErrInternal          = &Error{"internal server error", CodeInternal}
 
 	ErrUserNotFound  = &Error{"user not found", CodeUserNotFound}
 	ErrInvalidUserID = &Error{"invalid user id", CodeInvalidUserID}
 	ErrInvalidToken  = &Error{"invalid token", CodeInvalidToken}
 	ErrUserConflict  = &Error{"user conflict", CodeUserConflict}
 
+	ErrEmailNotVerified     = &Error{"email not verified", CodeEmailNotVerified}
+	ErrEmailAlreadyVerified = &Error{"email already verified", CodeEmailAlreadyVerified}
+
+	ErrTwoFactorEnabled    = &Error{"two-factor authentication already enabled", CodeTwoFactorEnabled}
+	ErrTwoFactorNotEnabled = &Error{"two-factor authentication not enabled", CodeTwoFactorNotEnabled}
+	ErrInvalidOTP          = &Error{"invalid one-time password", CodeInvalidOTP}
+
+	ErrProviderNotFound = &Error{"identity provider not found", CodeProviderNotFound}
+	ErrExternalLogin    = &Error{"external login failed", CodeExternalLogin}
+
+	ErrAccessTokenNotFound = &Error{"access token not found", CodeAccessTokenNotFound}
+
+	ErrPasskeyNotFound = &Error{"passkey not found", CodePasskeyNotFound}
+	ErrInvalidPasskey  = &Error{"passkey verification failed", CodeInvalidPasskey}
+
+	ErrOAuthClientNotFound = &Error{"oauth client not found", CodeOAuthClientNotFound}
+	ErrInvalidRedirectURI  = &Error{"invalid redirect uri", CodeInvalidRedirectURI}
+
+	ErrTokenExpired   = &Error{"token expired", CodeTokenExpired}
+	ErrMalformedToken = &Error{"malformed token", CodeMalformedToken}
+	ErrWrongTokenType = &Error{"wrong token type", CodeWrongTokenType}
+
+	ErrProviderUnavailable = &Error{"identity provider unavailable", CodeProviderUnavailable}
+
 	ErrResumeNotFound  = &Error{"resume not found", CodeResumeNotFound}
 	ErrInvalidResumeID = &Error{"invalid resume id", CodeInvalidResumeID}
+	ErrExportFailed    = &Error{"failed to export resume", CodeExportFailed}
+
+	ErrInvalidTemplate  = &Error{"invalid template", CodeInvalidTemplate}
+	ErrTemplateNotFound = &Error{"template not found", CodeTemplateNotFound}
+	ErrSlugConflict     = &Error{"slug already in use", CodeSlugConflict}
+
+	ErrUnsupportedFormat = &Error{"unsupported format", CodeUnsupportedFormat}
+	ErrInvalidImport     = &Error{"invalid import file", CodeInvalidImport}
+
+	ErrSandboxBusy = &Error{"too many templates are being rendered", CodeSandboxBusy}
 )
 
 type Error struct {
@@ -47,6 +132,74 @@ func (err *Error) Error() string {
 	return err.Message
 }
 
+// FieldError describes why a field was rejected. Line and Column locate the problem
+// inside multi-line values such as template sources.
+type FieldError struct {
+	Field   string `json:"field"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// ValidationError is an Error with details about which fields were rejected and why.
+// It unwraps to the Error it was created from, so errors.Is keeps working.
+type ValidationError struct {
+	Message string       `json:"message"`
+	Code    int          `json:"code"`
+	Fields  []FieldError `json:"fields"`
+	base    *Error
+}
+
+func NewValidationError(base *Error, fields ...FieldError) *ValidationError {
+	return &ValidationError{
+		Message: base.Message,
+		Code:    base.Code,
+		Fields:  fields,
+		base:    base,
+	}
+}
+
+func (err *ValidationError) Error() string {
+	return err.Message
+}
+
+func (err *ValidationError) Unwrap() error {
+	return err.base
+}
+
+// BearerChallenge builds a WWW-Authenticate value for bearer token errors as described in
+// RFC 6750. An empty kind yields the bare challenge sent when no credentials were given.
+func BearerChallenge(kind, description, scope string) string {
+	challenge := `Bearer realm="paperless.dev"`
+	if kind == "" {
+		return challenge
+	}
+
+	challenge += fmt.Sprintf(`, error=%q`, kind)
+	if description != "" {
+		challenge += fmt.Sprintf(`, error_description=%q`, description)
+	}
+	if scope != "" {
+		challenge += fmt.Sprintf(`, scope=%q`, scope)
+	}
+	return challenge
+}
+
+func bearerChallenge(err *Error) (string, bool) {
+	switch err.Code {
+	case CodeUnauthorized:
+		return BearerChallenge("", "", ""), true
+	case CodeInvalidAuthHeader:
+		return BearerChallenge("invalid_request", err.Message, ""), true
+	case CodeInvalidToken, CodeTokenExpired, CodeMalformedToken, CodeWrongTokenType:
+		return BearerChallenge("invalid_token", err.Message, ""), true
+	case CodeInsufficientScope:
+		return BearerChallenge("insufficient_scope", err.Message, ""), true
+	}
+	return "", false
+}
+
 func ErrorHandler(c *gin.Context) {
 	c.Next()
 
@@ -55,24 +208,56 @@ func ErrorHandler(c *gin.Context) {
 	if lastErr != nil {
 		var err *Error
 		if errors.As(lastErr.Err, &err) {
-			log.Println(err.Message)
-
-			status := http.StatusInternalServerError
-
-			switch err.Code {
-			case CodeInvalidInput, CodeInvalidUserID, CodeInvalidResumeID:
-				status = http.StatusBadRequest
-			case CodeInvalidToken:
-				status = http.StatusUnauthorized
-			case CodeAccessDenied:
-				status = http.StatusForbidden
-			case CodeUserNotFound, CodeResumeNotFound:
-				status = http.StatusNotFound
-			case CodeUserConflict:
-				status = http.StatusConflict
-			}
-
-			c.AbortWithStatusJSON(status, gin.H{"error": err})
+			AbortWithError(c, lastErr.Err)
 		}
 	}
 }
+
+// AbortWithError writes err with the status its code maps to. Middleware that runs before
+// ErrorHandler, such as the auth protector, uses it to respond right away.
+func AbortWithError(c *gin.Context, e error) {
+	var err *Error
+	if !errors.As(e, &err) {
+		log.Println(e)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	log.Println(err.Message)
+
+	status := http.StatusInternalServerError
+
+	switch err.Code {
+	case CodeInvalidInput, CodeInvalidUserID, CodeInvalidResumeID, CodeWeakPassword, CodeInvalidRedirectURI, CodeInvalidAuthHeader,
+		CodeInvalidTemplate, CodeUnsupportedFormat, CodeInvalidImport:
+		status = http.StatusBadRequest
+	case CodeUnauthorized, CodeInvalidToken, CodeInvalidOTP, CodeExternalLogin, CodeInvalidPasskey,
+		CodeTokenExpired, CodeMalformedToken, CodeWrongTokenType:
+		status = http.StatusUnauthorized
+	case CodeAccessDenied, CodeEmailNotVerified, CodeInsufficientScope, CodeInvalidCSRFToken, CodeImpersonation:
+		status = http.StatusForbidden
+	case CodeUserNotFound, CodeResumeNotFound, CodeProviderNotFound, CodeAccessTokenNotFound, CodePasskeyNotFound, CodeOAuthClientNotFound,
+		CodeTemplateNotFound:
+		status = http.StatusNotFound
+	case CodeUserConflict, CodeEmailAlreadyVerified, CodeTwoFactorEnabled, CodeTwoFactorNotEnabled, CodeSlugConflict:
+		status = http.StatusConflict
+	case CodeTooManyAttempts:
+		status = http.StatusTooManyRequests
+	case CodeProviderUnavailable:
+		status = http.StatusBadGateway
+	case CodeSandboxBusy:
+		status = http.StatusServiceUnavailable
+	}
+
+	if challenge, ok := bearerChallenge(err); ok && c.Writer.Header().Get("WWW-Authenticate") == "" {
+		c.Header("WWW-Authenticate", challenge)
+	}
+
+	var body any = err
+	var validationErr *ValidationError
+	if errors.As(e, &validationErr) {
+		body = validationErr
+	}
+
+	c.AbortWithStatusJSON(status, gin.H{"error": body})
+}
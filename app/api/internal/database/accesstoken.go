@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// AccessToken is a user-managed personal access token. Only the hash of the secret
+// is stored; Prefix keeps the first characters so users can tell tokens apart.
+type AccessToken struct {
+	ID         bson.ObjectID `bson:"_id,omitempty"`
+	UserID     bson.ObjectID `bson:"userID"`
+	Name       string        `bson:"name"`
+	Hash       string        `bson:"hash"`
+	Prefix     string        `bson:"prefix"`
+	Scopes     []string      `bson:"scopes"`
+	ExpiresAt  *time.Time    `bson:"expiresAt,omitempty"`
+	LastUsedAt *time.Time    `bson:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time     `bson:"createdAt"`
+}
+
+func (token *AccessToken) ResponseSchema() *schema.AccessTokenResponseSchema {
+	s := new(schema.AccessTokenResponseSchema)
+	s.ID = token.ID.Hex()
+	s.Name = token.Name
+	s.Prefix = token.Prefix
+	s.Scopes = token.Scopes
+	s.ExpiresAt = token.ExpiresAt
+	s.LastUsedAt = token.LastUsedAt
+	s.CreatedAt = token.CreatedAt
+	return s
+}
+
+func (token *AccessToken) Expired() bool {
+	return token.ExpiresAt != nil && time.Now().After(*token.ExpiresAt)
+}
+
+type AccessTokenRepository interface {
+	Create(token *AccessToken) (*AccessToken, error)
+	FindByHash(hash string) (*AccessToken, error)
+	FindManyByUserID(userID string) ([]AccessToken, error)
+	Touch(id bson.ObjectID) error
+	DeleteByID(userID, id string) error
+	DeleteByUserID(userID string) error
+}
+
+type MongoAccessTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAccessTokenRepository() AccessTokenRepository {
+	return &MongoAccessTokenRepository{
+		collection: mongoDatabase.Collection("accessTokens"),
+	}
+}
+
+func (r *MongoAccessTokenRepository) Create(token *AccessToken) (*AccessToken, error) {
+	doc := *token
+	doc.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
+func (r *MongoAccessTokenRepository) FindByHash(hash string) (*AccessToken, error) {
+	var token AccessToken
+	err := r.collection.FindOne(context.Background(), bson.M{"hash": hash}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrInvalidToken
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &token, nil
+}
+
+func (r *MongoAccessTokenRepository) FindManyByUserID(userID string) ([]AccessToken, error) {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	cursor, err := r.collection.Find(context.Background(), bson.M{"userID": userObjID})
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	var result []AccessToken
+	if err = cursor.All(context.Background(), &result); err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	return result, nil
+}
+
+func (r *MongoAccessTokenRepository) Touch(id bson.ObjectID) error {
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"lastUsedAt": time.Now()}})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
+
+func (r *MongoAccessTokenRepository) DeleteByID(userID, id string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return common.ErrAccessTokenNotFound
+	}
+
+	result, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": objID, "userID": userObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	if result.DeletedCount == 0 {
+		return common.ErrAccessTokenNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoAccessTokenRepository) DeleteByUserID(userID string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	_, err = r.collection.DeleteMany(context.Background(), bson.M{"userID": userObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	AuditActionImpersonationStart = "impersonation.start"
+	AuditActionRequest            = "request"
+)
+
+// AuditLog records an action an admin took on behalf of another user.
+type AuditLog struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	ActorID   bson.ObjectID `bson:"actorID"`
+	SubjectID bson.ObjectID `bson:"subjectID"`
+	Action    string        `bson:"action"`
+	Reason    string        `bson:"reason,omitempty"`
+	Method    string        `bson:"method,omitempty"`
+	Path      string        `bson:"path,omitempty"`
+	Status    int           `bson:"status,omitempty"`
+	IP        string        `bson:"ip"`
+	UserAgent string        `bson:"userAgent"`
+	CreatedAt time.Time     `bson:"createdAt"`
+}
+
+func (log *AuditLog) ResponseSchema() *schema.AuditLogResponseSchema {
+	s := new(schema.AuditLogResponseSchema)
+	s.ID = log.ID.Hex()
+	s.ActorID = log.ActorID.Hex()
+	s.SubjectID = log.SubjectID.Hex()
+	s.Action = log.Action
+	s.Reason = log.Reason
+	s.Method = log.Method
+	s.Path = log.Path
+	s.Status = log.Status
+	s.IP = log.IP
+	s.UserAgent = log.UserAgent
+	s.CreatedAt = log.CreatedAt
+	return s
+}
+
+type AuditLogFilter struct {
+	ActorID   string
+	SubjectID string
+	Limit     int64
+}
+
+type AuditLogRepository interface {
+	Create(log *AuditLog) (*AuditLog, error)
+	FindMany(filter AuditLogFilter) ([]AuditLog, error)
+}
+
+type MongoAuditLogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditLogRepository() AuditLogRepository {
+	return &MongoAuditLogRepository{
+		collection: mongoDatabase.Collection("auditLogs"),
+	}
+}
+
+func (r *MongoAuditLogRepository) Create(log *AuditLog) (*AuditLog, error) {
+	doc := *log
+	doc.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
+// FindMany returns the newest entries first, optionally limited to one actor or subject.
+func (r *MongoAuditLogRepository) FindMany(filter AuditLogFilter) ([]AuditLog, error) {
+	query := bson.M{}
+	if filter.ActorID != "" {
+		actorID, err := bson.ObjectIDFromHex(filter.ActorID)
+		if err != nil {
+			return nil, common.ErrInvalidUserID
+		}
+		query["actorID"] = actorID
+	}
+	if filter.SubjectID != "" {
+		subjectID, err := bson.ObjectIDFromHex(filter.SubjectID)
+		if err != nil {
+			return nil, common.ErrInvalidUserID
+		}
+		query["subjectID"] = subjectID
+	}
+
+	opt := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(filter.Limit)
+	cursor, err := r.collection.Find(context.Background(), query, opt)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	var result []AuditLog
+	if err = cursor.All(context.Background(), &result); err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	LoginFailureUnknownUser     = "unknown_user"
+	LoginFailureInvalidPassword = "invalid_password"
+	LoginFailureInvalidCode     = "invalid_code"
+	LoginFailureInvalidPasskey  = "invalid_passkey"
+	LoginFailureThrottled       = "throttled"
+)
+
+// Login is one sign-in attempt. UserID is empty when the identifier did not match an account.
+type Login struct {
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+	UserID      bson.ObjectID `bson:"userID,omitempty"`
+	Identifier  string        `bson:"identifier,omitempty"`
+	Method      string        `bson:"method"`
+	Success     bool          `bson:"success"`
+	Failure     string        `bson:"failure,omitempty"`
+	IP          string        `bson:"ip"`
+	UserAgent   string        `bson:"userAgent"`
+	Fingerprint string        `bson:"fingerprint"`
+	NewDevice   bool          `bson:"newDevice,omitempty"`
+	CreatedAt   time.Time     `bson:"createdAt"`
+}
+
+func (login *Login) ResponseSchema() *schema.LoginResponseSchema {
+	s := new(schema.LoginResponseSchema)
+	s.ID = login.ID.Hex()
+	s.Method = login.Method
+	s.Success = login.Success
+	s.Failure = login.Failure
+	s.IP = login.IP
+	s.UserAgent = login.UserAgent
+	s.NewDevice = login.NewDevice
+	s.CreatedAt = login.CreatedAt
+	return s
+}
+
+type LoginRepository interface {
+	Create(login *Login) (*Login, error)
+	FindManyByUserID(userID string, limit int64) ([]Login, error)
+	HasSuccessfulLogin(userID bson.ObjectID, fingerprint string) (bool, error)
+	DeleteByUserID(userID string) error
+}
+
+type MongoLoginRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoginRepository() LoginRepository {
+	return &MongoLoginRepository{
+		collection: mongoDatabase.Collection("logins"),
+	}
+}
+
+func (r *MongoLoginRepository) Create(login *Login) (*Login, error) {
+	doc := *login
+	doc.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
+// FindManyByUserID returns the most recent attempts first.
+func (r *MongoLoginRepository) FindManyByUserID(userID string, limit int64) ([]Login, error) {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	opt := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(context.Background(), bson.M{"userID": userObjID}, opt)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	var result []Login
+	if err = cursor.All(context.Background(), &result); err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	return result, nil
+}
+
+// HasSuccessfulLogin reports whether the user has signed in before, from the device with
+// the given fingerprint or, when fingerprint is empty, from any device.
+func (r *MongoLoginRepository) HasSuccessfulLogin(userID bson.ObjectID, fingerprint string) (bool, error) {
+	filter := bson.M{"userID": userID, "success": true}
+	if fingerprint != "" {
+		filter["fingerprint"] = fingerprint
+	}
+
+	err := r.collection.FindOne(context.Background(), filter).Err()
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	return false, common.ErrDatabase
+}
+
+func (r *MongoLoginRepository) DeleteByUserID(userID string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	_, err = r.collection.DeleteMany(context.Background(), bson.M{"userID": userObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
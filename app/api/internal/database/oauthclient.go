@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// OAuthClient is a third-party application registered to request access to user data.
+// Public clients have no secret and must rely on PKCE alone.
+type OAuthClient struct {
+	ID           bson.ObjectID `bson:"_id,omitempty"`
+	ClientID     string        `bson:"clientID"`
+	SecretHash   string        `bson:"secretHash,omitempty"`
+	OwnerID      bson.ObjectID `bson:"ownerID"`
+	Name         string        `bson:"name"`
+	Website      string        `bson:"website,omitempty"`
+	RedirectURIs []string      `bson:"redirectURIs"`
+	Scopes       []string      `bson:"scopes"`
+	CreatedAt    time.Time     `bson:"createdAt"`
+}
+
+func (client *OAuthClient) Confidential() bool {
+	return client.SecretHash != ""
+}
+
+func (client *OAuthClient) ResponseSchema() *schema.OAuthClientResponseSchema {
+	s := new(schema.OAuthClientResponseSchema)
+	s.ID = client.ID.Hex()
+	s.ClientID = client.ClientID
+	s.Name = client.Name
+	s.Website = client.Website
+	s.RedirectURIs = client.RedirectURIs
+	s.Scopes = client.Scopes
+	s.Confidential = client.Confidential()
+	s.CreatedAt = client.CreatedAt
+	return s
+}
+
+type OAuthClientRepository interface {
+	Create(client *OAuthClient) (*OAuthClient, error)
+	FindByClientID(clientID string) (*OAuthClient, error)
+	FindManyByOwnerID(ownerID string) ([]OAuthClient, error)
+	DeleteByID(ownerID, id string) (*OAuthClient, error)
+}
+
+type MongoOAuthClientRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOAuthClientRepository() OAuthClientRepository {
+	return &MongoOAuthClientRepository{
+		collection: mongoDatabase.Collection("oauthClients"),
+	}
+}
+
+func (r *MongoOAuthClientRepository) Create(client *OAuthClient) (*OAuthClient, error) {
+	doc := *client
+	doc.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
+func (r *MongoOAuthClientRepository) FindByClientID(clientID string) (*OAuthClient, error) {
+	var client OAuthClient
+	err := r.collection.FindOne(context.Background(), bson.M{"clientID": clientID}).Decode(&client)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrOAuthClientNotFound
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &client, nil
+}
+
+func (r *MongoOAuthClientRepository) FindManyByOwnerID(ownerID string) ([]OAuthClient, error) {
+	ownerObjID, err := bson.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	cursor, err := r.collection.Find(context.Background(), bson.M{"ownerID": ownerObjID})
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	var result []OAuthClient
+	if err = cursor.All(context.Background(), &result); err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	return result, nil
+}
+
+func (r *MongoOAuthClientRepository) DeleteByID(ownerID, id string) (*OAuthClient, error) {
+	ownerObjID, err := bson.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, common.ErrOAuthClientNotFound
+	}
+
+	var client OAuthClient
+	err = r.collection.FindOneAndDelete(context.Background(), bson.M{"_id": objID, "ownerID": ownerObjID}).Decode(&client)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrOAuthClientNotFound
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &client, nil
+}
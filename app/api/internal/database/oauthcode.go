@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// OAuthCode is an authorization code waiting to be exchanged at the token endpoint.
+// GrantID is set once the code has been exchanged so a replayed code can revoke the grant.
+type OAuthCode struct {
+	ID            bson.ObjectID `bson:"_id,omitempty"`
+	Hash          string        `bson:"hash"`
+	ClientID      string        `bson:"clientID"`
+	UserID        bson.ObjectID `bson:"userID"`
+	RedirectURI   string        `bson:"redirectURI"`
+	Scopes        []string      `bson:"scopes"`
+	CodeChallenge string        `bson:"codeChallenge"`
+	GrantID       bson.ObjectID `bson:"grantID,omitempty"`
+	ExpiresAt     time.Time     `bson:"expiresAt"`
+	UsedAt        *time.Time    `bson:"usedAt,omitempty"`
+	CreatedAt     time.Time     `bson:"createdAt"`
+}
+
+type OAuthCodeRepository interface {
+	Create(code *OAuthCode) (*OAuthCode, error)
+	Consume(hash string) (*OAuthCode, error)
+	FindUsed(hash string) (*OAuthCode, error)
+	SetGrant(id, grantID bson.ObjectID) error
+	DeleteByUserID(userID string) error
+}
+
+type MongoOAuthCodeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOAuthCodeRepository() OAuthCodeRepository {
+	return &MongoOAuthCodeRepository{
+		collection: mongoDatabase.Collection("oauthCodes"),
+	}
+}
+
+func (r *MongoOAuthCodeRepository) Create(code *OAuthCode) (*OAuthCode, error) {
+	doc := *code
+	doc.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
+// Consume marks an unused, unexpired code as used and returns it.
+// It fails with ErrInvalidToken when the code is unknown, expired or already used.
+func (r *MongoOAuthCodeRepository) Consume(hash string) (*OAuthCode, error) {
+	now := time.Now()
+	filter := bson.M{
+		"hash":      hash,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"usedAt": now}}
+	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var code OAuthCode
+	err := r.collection.FindOneAndUpdate(context.Background(), filter, update, opt).Decode(&code)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrInvalidToken
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &code, nil
+}
+
+func (r *MongoOAuthCodeRepository) FindUsed(hash string) (*OAuthCode, error) {
+	var code OAuthCode
+	err := r.collection.FindOne(context.Background(), bson.M{"hash": hash, "usedAt": bson.M{"$exists": true}}).Decode(&code)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrInvalidToken
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &code, nil
+}
+
+func (r *MongoOAuthCodeRepository) SetGrant(id, grantID bson.ObjectID) error {
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"grantID": grantID}})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
+
+func (r *MongoOAuthCodeRepository) DeleteByUserID(userID string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	_, err = r.collection.DeleteMany(context.Background(), bson.M{"userID": userObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// OAuthGrant is the access a user gave a client. Access tokens reference it by ID and the
+// current refresh token is stored as a hash, so revoking the grant ends both.
+type OAuthGrant struct {
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+	ClientID    string        `bson:"clientID"`
+	UserID      bson.ObjectID `bson:"userID"`
+	Scopes      []string      `bson:"scopes"`
+	RefreshHash string        `bson:"refreshHash"`
+	RevokedAt   *time.Time    `bson:"revokedAt,omitempty"`
+	RefreshedAt *time.Time    `bson:"refreshedAt,omitempty"`
+	CreatedAt   time.Time     `bson:"createdAt"`
+}
+
+func (grant *OAuthGrant) Active() bool {
+	return grant.RevokedAt == nil
+}
+
+type OAuthGrantRepository interface {
+	Create(grant *OAuthGrant) (*OAuthGrant, error)
+	FindByID(id string) (*OAuthGrant, error)
+	FindByRefreshHash(hash string) (*OAuthGrant, error)
+	FindManyActive(userID bson.ObjectID, clientID string) ([]OAuthGrant, error)
+	RotateRefreshHash(id bson.ObjectID, oldHash, newHash string) error
+	Revoke(id bson.ObjectID) error
+	RevokeByClientID(clientID string) error
+	DeleteByUserID(userID string) error
+}
+
+type MongoOAuthGrantRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOAuthGrantRepository() OAuthGrantRepository {
+	return &MongoOAuthGrantRepository{
+		collection: mongoDatabase.Collection("oauthGrants"),
+	}
+}
+
+func (r *MongoOAuthGrantRepository) Create(grant *OAuthGrant) (*OAuthGrant, error) {
+	doc := *grant
+	doc.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
+func (r *MongoOAuthGrantRepository) FindByID(id string) (*OAuthGrant, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, common.ErrInvalidToken
+	}
+
+	return r.findOne(bson.M{"_id": objID})
+}
+
+func (r *MongoOAuthGrantRepository) FindByRefreshHash(hash string) (*OAuthGrant, error) {
+	return r.findOne(bson.M{"refreshHash": hash})
+}
+
+func (r *MongoOAuthGrantRepository) findOne(filter bson.M) (*OAuthGrant, error) {
+	var grant OAuthGrant
+	err := r.collection.FindOne(context.Background(), filter).Decode(&grant)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrInvalidToken
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &grant, nil
+}
+
+func (r *MongoOAuthGrantRepository) FindManyActive(userID bson.ObjectID, clientID string) ([]OAuthGrant, error) {
+	filter := bson.M{"userID": userID, "clientID": clientID, "revokedAt": bson.M{"$exists": false}}
+
+	cursor, err := r.collection.Find(context.Background(), filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	var result []OAuthGrant
+	if err = cursor.All(context.Background(), &result); err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	return result, nil
+}
+
+// RotateRefreshHash replaces the refresh token hash only if oldHash is still current, so two
+// concurrent refreshes with the same token cannot both succeed.
+func (r *MongoOAuthGrantRepository) RotateRefreshHash(id bson.ObjectID, oldHash, newHash string) error {
+	filter := bson.M{"_id": id, "refreshHash": oldHash, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"refreshHash": newHash, "refreshedAt": time.Now()}}
+
+	result, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return common.ErrInvalidToken
+	}
+
+	return nil
+}
+
+func (r *MongoOAuthGrantRepository) Revoke(id bson.ObjectID) error {
+	filter := bson.M{"_id": id, "revokedAt": bson.M{"$exists": false}}
+
+	_, err := r.collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"revokedAt": time.Now()}})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
+
+func (r *MongoOAuthGrantRepository) RevokeByClientID(clientID string) error {
+	filter := bson.M{"clientID": clientID, "revokedAt": bson.M{"$exists": false}}
+
+	_, err := r.collection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"revokedAt": time.Now()}})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
+
+func (r *MongoOAuthGrantRepository) DeleteByUserID(userID string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	_, err = r.collection.DeleteMany(context.Background(), bson.M{"userID": userObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
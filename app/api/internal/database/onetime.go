@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// OneTimeToken is a single-use secret such as a password reset link.
+// Only the hash of the secret is stored.
+type OneTimeToken struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	UserID    bson.ObjectID `bson:"userID"`
+	Purpose   string        `bson:"purpose"`
+	Hash      string        `bson:"hash"`
+	ExpiresAt time.Time     `bson:"expiresAt"`
+	UsedAt    *time.Time    `bson:"usedAt,omitempty"`
+	CreatedAt time.Time     `bson:"createdAt"`
+}
+
+type OneTimeTokenRepository interface {
+	Create(userID, purpose, hash string, expiresAt time.Time) (*OneTimeToken, error)
+	Find(purpose, hash string) (*OneTimeToken, error)
+	Consume(purpose, hash string) (*OneTimeToken, error)
+	DeleteByUserID(userID, purpose string) error
+}
+
+type MongoOneTimeTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOneTimeTokenRepository() OneTimeTokenRepository {
+	return &MongoOneTimeTokenRepository{
+		collection: mongoDatabase.Collection("oneTimeTokens"),
+	}
+}
+
+func (r *MongoOneTimeTokenRepository) Create(userID, purpose, hash string, expiresAt time.Time) (*OneTimeToken, error) {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	doc := &OneTimeToken{
+		UserID:    userObjID,
+		Purpose:   purpose,
+		Hash:      hash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	result, err := r.collection.InsertOne(context.Background(), doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return doc, nil
+}
+
+// Find returns an unused, unexpired token without using it up.
+// It fails with ErrInvalidToken when the token is unknown, expired or already used.
+func (r *MongoOneTimeTokenRepository) Find(purpose, hash string) (*OneTimeToken, error) {
+	filter := bson.M{
+		"purpose":   purpose,
+		"hash":      hash,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+
+	var token OneTimeToken
+	err := r.collection.FindOne(context.Background(), filter).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrInvalidToken
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &token, nil
+}
+
+// Consume marks an unused, unexpired token as used and returns it.
+// It fails with ErrInvalidToken when the token is unknown, expired or already used.
+func (r *MongoOneTimeTokenRepository) Consume(purpose, hash string) (*OneTimeToken, error) {
+	now := time.Now()
+	filter := bson.M{
+		"purpose":   purpose,
+		"hash":      hash,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"usedAt": now}}
+	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var token OneTimeToken
+	err := r.collection.FindOneAndUpdate(context.Background(), filter, update, opt).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrInvalidToken
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &token, nil
+}
+
+// DeleteByUserID deletes the user's tokens for purpose, or all of them when purpose is empty.
+func (r *MongoOneTimeTokenRepository) DeleteByUserID(userID, purpose string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	filter := bson.M{"userID": userObjID}
+	if purpose != "" {
+		filter["purpose"] = purpose
+	}
+
+	_, err = r.collection.DeleteMany(context.Background(), filter)
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Passkey is a WebAuthn credential registered by a user. A user can have several.
+type Passkey struct {
+	ID         bson.ObjectID       `bson:"_id,omitempty"`
+	UserID     bson.ObjectID       `bson:"userID"`
+	Name       string              `bson:"name"`
+	Credential webauthn.Credential `bson:"credential"`
+	LastUsedAt *time.Time          `bson:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time           `bson:"createdAt"`
+}
+
+func (passkey *Passkey) ResponseSchema() *schema.PasskeyResponseSchema {
+	s := new(schema.PasskeyResponseSchema)
+	s.ID = passkey.ID.Hex()
+	s.Name = passkey.Name
+	s.BackupEligible = passkey.Credential.Flags.BackupEligible
+	s.SignCount = passkey.Credential.Authenticator.SignCount
+	s.LastUsedAt = passkey.LastUsedAt
+	s.CreatedAt = passkey.CreatedAt
+	return s
+}
+
+type PasskeyRepository interface {
+	Create(passkey *Passkey) (*Passkey, error)
+	FindByCredentialID(credentialID []byte) (*Passkey, error)
+	FindManyByUserID(userID string) ([]Passkey, error)
+	UpdateCredential(id bson.ObjectID, credential *webauthn.Credential) error
+	DeleteByID(userID, id string) error
+	DeleteByUserID(userID string) error
+}
+
+type MongoPasskeyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPasskeyRepository() PasskeyRepository {
+	return &MongoPasskeyRepository{
+		collection: mongoDatabase.Collection("passkeys"),
+	}
+}
+
+func (r *MongoPasskeyRepository) Create(passkey *Passkey) (*Passkey, error) {
+	doc := *passkey
+	doc.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
+func (r *MongoPasskeyRepository) FindByCredentialID(credentialID []byte) (*Passkey, error) {
+	var passkey Passkey
+	err := r.collection.FindOne(context.Background(), bson.M{"credential.id": credentialID}).Decode(&passkey)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrPasskeyNotFound
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &passkey, nil
+}
+
+func (r *MongoPasskeyRepository) FindManyByUserID(userID string) ([]Passkey, error) {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	cursor, err := r.collection.Find(context.Background(), bson.M{"userID": userObjID})
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	var result []Passkey
+	if err = cursor.All(context.Background(), &result); err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	return result, nil
+}
+
+// UpdateCredential stores the sign count and flags reported by the latest assertion.
+func (r *MongoPasskeyRepository) UpdateCredential(id bson.ObjectID, credential *webauthn.Credential) error {
+	update := bson.M{"$set": bson.M{"credential": credential, "lastUsedAt": time.Now()}}
+
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, update)
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
+
+func (r *MongoPasskeyRepository) DeleteByID(userID, id string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return common.ErrPasskeyNotFound
+	}
+
+	result, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": objID, "userID": userObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	if result.DeletedCount == 0 {
+		return common.ErrPasskeyNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoPasskeyRepository) DeleteByUserID(userID string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	_, err = r.collection.DeleteMany(context.Background(), bson.M{"userID": userObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
@@ -53,6 +53,7 @@ type Resume struct {
 	Image       string        `bson:"image,omitempty"`
 	Public      bool          `bson:"public"`
 	Template    string        `bson:"template,omitempty"`
+	Slug        string        `bson:"slug,omitempty"`
 	Skills      []string      `bson:"skills,omitempty"`
 	Experiences []Experience  `bson:"experiences,omitempty"`
 	Educations  []Education   `bson:"educations,omitempty"`
@@ -71,6 +72,7 @@ func (resume *Resume) ResponseSchema() *schema.ResumeResponseSchema {
 	s.Image = resume.Image
 	s.Public = resume.Public
 	s.Template = resume.Template
+	s.Slug = resume.Slug
 	s.Skills = resume.Skills
 	s.CreatedAt = resume.CreatedAt
 	s.UpdatedAt = resume.UpdatedAt
@@ -120,8 +122,11 @@ func (resume *Resume) ResponseSchema() *schema.ResumeResponseSchema {
 
 type ResumeRepository interface {
 	Create(schema *schema.ResumeCreateSchema) (*Resume, error)
+	Insert(resume *Resume) (*Resume, error)
 	FindByID(id string) (*Resume, error)
 	FindManyByOwnerID(ownerID string) ([]Resume, error)
+	FindByOwnerAndSlug(ownerID, slug string) (*Resume, error)
+	FindManyPublic(limit int64) ([]Resume, error)
 	Update(id string, schema *schema.ResumeUpdateSchema) (*Resume, error)
 	DeleteByID(id string) error
 }
@@ -149,6 +154,7 @@ func (r *MongoResumeRepository) Create(schema *schema.ResumeCreateSchema) (*Resu
 		Description: schema.Description,
 		Public:      schema.Public,
 		Template:    schema.Template,
+		Slug:        schema.Slug,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -160,6 +166,21 @@ func (r *MongoResumeRepository) Create(schema *schema.ResumeCreateSchema) (*Resu
 	return &doc, nil
 }
 
+// Insert stores a complete resume, such as one produced by an import.
+func (r *MongoResumeRepository) Insert(resume *Resume) (*Resume, error) {
+	doc := *resume
+	doc.ID = bson.ObjectID{}
+	doc.CreatedAt = time.Now()
+	doc.UpdatedAt = doc.CreatedAt
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
 func (r *MongoResumeRepository) FindByID(id string) (*Resume, error) {
 	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
@@ -197,6 +218,44 @@ func (r *MongoResumeRepository) FindManyByOwnerID(ownerID string) ([]Resume, err
 	return result, nil
 }
 
+func (r *MongoResumeRepository) FindByOwnerAndSlug(ownerID, slug string) (*Resume, error) {
+	ownerObjID, err := bson.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	doc := new(Resume)
+	err = r.collection.FindOne(context.Background(), bson.M{"ownerID": ownerObjID, "slug": slug}).Decode(doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrResumeNotFound
+		}
+		return nil, common.ErrDatabase
+	}
+	return doc, nil
+}
+
+// FindManyPublic returns the ID, owner, slug and modification time of up to limit
+// public resumes, most recently updated first.
+func (r *MongoResumeRepository) FindManyPublic(limit int64) ([]Resume, error) {
+	opt := options.Find().
+		SetProjection(bson.M{"_id": 1, "ownerID": 1, "slug": 1, "updatedAt": 1}).
+		SetSort(bson.D{{Key: "updatedAt", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(context.Background(), bson.M{"public": true}, opt)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	var result []Resume
+	if err = cursor.All(context.Background(), &result); err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	return result, nil
+}
+
 func (r *MongoResumeRepository) Update(id string, updateSchema *schema.ResumeUpdateSchema) (*Resume, error) {
 	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
@@ -226,6 +285,9 @@ func (r *MongoResumeRepository) Update(id string, updateSchema *schema.ResumeUpd
 	if updateSchema.Template != nil {
 		updateFields["template"] = *updateSchema.Template
 	}
+	if updateSchema.Slug != nil {
+		updateFields["slug"] = *updateSchema.Slug
+	}
 	if updateSchema.Skills != nil {
 		updateFields["skills"] = *updateSchema.Skills
 	}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	SecurityEventLockout   = "lockout"
+	SecurityEventNewDevice = "new_device"
+)
+
+// SecurityEvent records something the account owner should know about, such as a lockout.
+type SecurityEvent struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	UserID    bson.ObjectID `bson:"userID"`
+	Type      string        `bson:"type"`
+	IP        string        `bson:"ip,omitempty"`
+	UserAgent string        `bson:"userAgent,omitempty"`
+	Notified  bool          `bson:"notified"`
+	CreatedAt time.Time     `bson:"createdAt"`
+}
+
+type SecurityEventRepository interface {
+	Create(event *SecurityEvent) (*SecurityEvent, error)
+	MarkNotified(id bson.ObjectID) error
+	DeleteByUserID(userID string) error
+}
+
+type MongoSecurityEventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSecurityEventRepository() SecurityEventRepository {
+	return &MongoSecurityEventRepository{
+		collection: mongoDatabase.Collection("securityEvents"),
+	}
+}
+
+func (r *MongoSecurityEventRepository) Create(event *SecurityEvent) (*SecurityEvent, error) {
+	doc := *event
+	doc.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
+func (r *MongoSecurityEventRepository) MarkNotified(id bson.ObjectID) error {
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"notified": true}})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
+
+func (r *MongoSecurityEventRepository) DeleteByUserID(userID string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	_, err = r.collection.DeleteMany(context.Background(), bson.M{"userID": userObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// CustomTemplate is a resume layout written by a user in html/template with CSS.
+// Public templates can be used by everyone, private ones only by their owner.
+type CustomTemplate struct {
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+	OwnerID     bson.ObjectID `bson:"ownerID"`
+	Name        string        `bson:"name"`
+	Description string        `bson:"description,omitempty"`
+	HTML        string        `bson:"html"`
+	CSS         string        `bson:"css,omitempty"`
+	Public      bool          `bson:"public"`
+	CreatedAt   time.Time     `bson:"createdAt"`
+	UpdatedAt   time.Time     `bson:"updatedAt"`
+}
+
+// ReadableBy reports whether userID may view and use the template.
+func (template *CustomTemplate) ReadableBy(userID string) bool {
+	return template.Public || template.OwnerID.Hex() == userID
+}
+
+func (template *CustomTemplate) ResponseSchema() *schema.TemplateResponseSchema {
+	s := new(schema.TemplateResponseSchema)
+	s.ID = template.ID.Hex()
+	s.Name = template.Name
+	s.Description = template.Description
+	s.Custom = true
+	s.OwnerID = template.OwnerID.Hex()
+	s.Public = template.Public
+	s.HTML = template.HTML
+	s.CSS = template.CSS
+	s.CreatedAt = &template.CreatedAt
+	s.UpdatedAt = &template.UpdatedAt
+	return s
+}
+
+type CustomTemplateRepository interface {
+	Create(template *CustomTemplate) (*CustomTemplate, error)
+	FindByID(id string) (*CustomTemplate, error)
+	FindManyByOwnerID(ownerID string) ([]CustomTemplate, error)
+	FindManyPublic() ([]CustomTemplate, error)
+	Update(ownerID, id string, schema *schema.TemplateUpdateSchema) (*CustomTemplate, error)
+	DeleteByID(ownerID, id string) error
+}
+
+type MongoCustomTemplateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCustomTemplateRepository() CustomTemplateRepository {
+	return &MongoCustomTemplateRepository{
+		collection: mongoDatabase.Collection("templates"),
+	}
+}
+
+func (r *MongoCustomTemplateRepository) Create(template *CustomTemplate) (*CustomTemplate, error) {
+	doc := *template
+	doc.CreatedAt = time.Now()
+	doc.UpdatedAt = doc.CreatedAt
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
+func (r *MongoCustomTemplateRepository) FindByID(id string) (*CustomTemplate, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, common.ErrTemplateNotFound
+	}
+
+	var template CustomTemplate
+	err = r.collection.FindOne(context.Background(), bson.M{"_id": objID}).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrTemplateNotFound
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &template, nil
+}
+
+func (r *MongoCustomTemplateRepository) FindManyByOwnerID(ownerID string) ([]CustomTemplate, error) {
+	ownerObjID, err := bson.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	return r.find(bson.M{"ownerID": ownerObjID})
+}
+
+func (r *MongoCustomTemplateRepository) FindManyPublic() ([]CustomTemplate, error) {
+	return r.find(bson.M{"public": true})
+}
+
+func (r *MongoCustomTemplateRepository) find(filter bson.M) ([]CustomTemplate, error) {
+	opt := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opt)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	var result []CustomTemplate
+	if err = cursor.All(context.Background(), &result); err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	return result, nil
+}
+
+func (r *MongoCustomTemplateRepository) Update(ownerID, id string, updateSchema *schema.TemplateUpdateSchema) (*CustomTemplate, error) {
+	ownerObjID, err := bson.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, common.ErrTemplateNotFound
+	}
+
+	updateFields := bson.M{}
+
+	if updateSchema.Name != nil {
+		updateFields["name"] = *updateSchema.Name
+	}
+	if updateSchema.Description != nil {
+		updateFields["description"] = *updateSchema.Description
+	}
+	if updateSchema.HTML != nil {
+		updateFields["html"] = *updateSchema.HTML
+	}
+	if updateSchema.CSS != nil {
+		updateFields["css"] = *updateSchema.CSS
+	}
+	if updateSchema.Public != nil {
+		updateFields["public"] = *updateSchema.Public
+	}
+
+	updateFields["updatedAt"] = time.Now()
+
+	filter := bson.M{"_id": objID, "ownerID": ownerObjID}
+	update := bson.M{"$set": updateFields}
+	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var template CustomTemplate
+	err = r.collection.FindOneAndUpdate(context.Background(), filter, update, opt).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrTemplateNotFound
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &template, nil
+}
+
+func (r *MongoCustomTemplateRepository) DeleteByID(ownerID, id string) error {
+	ownerObjID, err := bson.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return common.ErrTemplateNotFound
+	}
+
+	result, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": objID, "ownerID": ownerObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+	if result.DeletedCount == 0 {
+		return common.ErrTemplateNotFound
+	}
+
+	return nil
+}
@@ -12,13 +12,27 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+// Identity links a user to an account at an external identity provider.
+type Identity struct {
+	Provider string `bson:"provider"`
+	Subject  string `bson:"subject"`
+}
+
 type User struct {
 	ID              bson.ObjectID `bson:"_id,omitempty"`
 	Username        string        `bson:"username"`
 	Email           string        `bson:"email"`
 	Password        string        `bson:"password,omitempty"`
 	Provider        string        `bson:"provider"`
+	Roles           []string      `bson:"roles,omitempty"`
+	Identities      []Identity    `bson:"identities,omitempty"`
 	IsEmailVerified bool          `bson:"isEmailVerified,omitempty"`
+	TokenVersion    int           `bson:"tokenVersion"`
+	TOTPEnabled     bool          `bson:"totpEnabled,omitempty"`
+	TOTPSecret      string        `bson:"totpSecret,omitempty"`
+	TOTPPending     string        `bson:"totpPending,omitempty"`
+	TOTPLastStep    int64         `bson:"totpLastStep,omitempty"`
+	RecoveryCodes   []string      `bson:"recoveryCodes,omitempty"`
 	CreatedAt       time.Time     `bson:"createdAt"`
 	UpdatedAt       time.Time     `bson:"updatedAt"`
 	LastLogin       time.Time     `bson:"lastLogin,omitempty"`
@@ -29,6 +43,8 @@ func (user *User) ResponseSchema() *schema.UserResponseSchema {
 	s.ID = user.ID.Hex()
 	s.Username = user.Username
 	s.Email = user.Email
+	s.EmailVerified = user.IsEmailVerified
+	s.TwoFactorEnabled = user.TOTPEnabled
 	s.CreatedAt = user.CreatedAt
 	s.UpdatedAt = user.UpdatedAt
 	return s
@@ -36,10 +52,24 @@ func (user *User) ResponseSchema() *schema.UserResponseSchema {
 
 type UserRepository interface {
 	Create(schema *schema.UserCreateSchema) (*User, error)
+	Insert(user *User) (*User, error)
 	FindByID(id string) (*User, error)
 	FindByUsername(username string) (*User, error)
+	FindByEmail(email string) (*User, error)
+	FindByIdentity(provider, subject string) (*User, error)
 	FindByUsernameOrEmail(username, email string) (*User, error)
 	Update(id string, schema *schema.UserUpdateSchema) (*User, error)
+	MarkEmailVerified(id, email string) (*User, error)
+	AddIdentity(id string, identity Identity) error
+	UpdatePassword(id, password string) error
+	SetPasswordHash(id, password string) error
+	UpdateLastLogin(id string) error
+	RevokeTokens(id string) error
+	SetPendingTOTP(id, secret string) error
+	EnableTOTP(id string, recoveryCodes []string, step int64) error
+	DisableTOTP(id string) error
+	AcceptTOTPStep(id string, step int64) error
+	ConsumeRecoveryCode(id, code string) error
 	DeleteByID(id string) error
 }
 
@@ -58,6 +88,7 @@ func (r *MongoUserRepository) Create(user *schema.UserCreateSchema) (*User, erro
 		Username:  user.Username,
 		Password:  user.Password,
 		Email:     user.Email,
+		Provider:  "local",
 		CreatedAt: time.Now(),
 	}
 
@@ -70,6 +101,20 @@ func (r *MongoUserRepository) Create(user *schema.UserCreateSchema) (*User, erro
 	return doc, nil
 }
 
+func (r *MongoUserRepository) Insert(user *User) (*User, error) {
+	doc := *user
+	doc.CreatedAt = time.Now()
+	doc.UpdatedAt = doc.CreatedAt
+
+	result, err := r.collection.InsertOne(context.Background(), &doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return &doc, nil
+}
+
 func (r *MongoUserRepository) FindByID(id string) (*User, error) {
 	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
@@ -101,6 +146,34 @@ func (r *MongoUserRepository) FindByUsername(username string) (*User, error) {
 	return &user, nil
 }
 
+func (r *MongoUserRepository) FindByEmail(email string) (*User, error) {
+	var user User
+	err := r.collection.FindOne(context.Background(), bson.M{"email": email}).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrUserNotFound
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &user, nil
+}
+
+func (r *MongoUserRepository) FindByIdentity(provider, subject string) (*User, error) {
+	filter := bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}}
+
+	var user User
+	err := r.collection.FindOne(context.Background(), filter).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrUserNotFound
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &user, nil
+}
+
 func (r *MongoUserRepository) FindByUsernameOrEmail(username, email string) (*User, error) {
 	filter := bson.M{
 		"$or": []bson.M{
@@ -129,10 +202,11 @@ func (r *MongoUserRepository) Update(id string, schema *schema.UserUpdateSchema)
 	updateFields := bson.M{}
 
 	if schema.Username != nil {
-		updateFields["Username"] = *schema.Username
+		updateFields["username"] = *schema.Username
 	}
 	if schema.Email != nil {
-		updateFields["Email"] = *schema.Email
+		updateFields["email"] = *schema.Email
+		updateFields["isEmailVerified"] = false
 	}
 
 	updateFields["updatedAt"] = time.Now()
@@ -153,6 +227,169 @@ func (r *MongoUserRepository) Update(id string, schema *schema.UserUpdateSchema)
 	return &updatedUser, nil
 }
 
+func (r *MongoUserRepository) MarkEmailVerified(id, email string) (*User, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, common.ErrInvalidUserID
+	}
+
+	filter := bson.M{"_id": objID, "email": email}
+	update := bson.M{"$set": bson.M{"isEmailVerified": true, "updatedAt": time.Now()}}
+	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updatedUser User
+	err = r.collection.FindOneAndUpdate(context.Background(), filter, update, opt).Decode(&updatedUser)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrUserNotFound
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &updatedUser, nil
+}
+
+// AddIdentity links an external identity and makes its provider the one the user
+// signs in with.
+func (r *MongoUserRepository) AddIdentity(id string, identity Identity) error {
+	return r.updateOne(id, bson.M{
+		"$addToSet": bson.M{"identities": identity},
+		"$set":      bson.M{"provider": identity.Provider, "updatedAt": time.Now()},
+	})
+}
+
+// UpdatePassword replaces the password hash and revokes every token issued before.
+func (r *MongoUserRepository) UpdatePassword(id, password string) error {
+	return r.updateOne(id, bson.M{
+		"$set": bson.M{"password": password, "updatedAt": time.Now()},
+		"$inc": bson.M{"tokenVersion": 1},
+	})
+}
+
+// SetPasswordHash replaces the stored hash without revoking sessions, e.g. to upgrade
+// the hashing algorithm of the same password.
+func (r *MongoUserRepository) SetPasswordHash(id, password string) error {
+	return r.updateOne(id, bson.M{"$set": bson.M{"password": password}})
+}
+
+// UpdateLastLogin records that the user signed in just now.
+func (r *MongoUserRepository) UpdateLastLogin(id string) error {
+	return r.updateOne(id, bson.M{"$set": bson.M{"lastLogin": time.Now()}})
+}
+
+// RevokeTokens invalidates every access and refresh token issued to the user so far.
+func (r *MongoUserRepository) RevokeTokens(id string) error {
+	return r.updateOne(id, bson.M{"$inc": bson.M{"tokenVersion": 1}})
+}
+
+// SetPendingTOTP stores a secret that becomes active once EnableTOTP confirms it.
+func (r *MongoUserRepository) SetPendingTOTP(id, secret string) error {
+	return r.updateOne(id, bson.M{"$set": bson.M{"totpPending": secret}})
+}
+
+// EnableTOTP promotes the pending secret and replaces the recovery code hashes. step is
+// the time step of the code that confirmed the secret, which cannot be used again.
+func (r *MongoUserRepository) EnableTOTP(id string, recoveryCodes []string, step int64) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	filter := bson.M{"_id": objID, "totpPending": bson.M{"$exists": true}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"totpEnabled":   true,
+		"totpSecret":    "$totpPending",
+		"recoveryCodes": recoveryCodes,
+		"totpLastStep":  step,
+		"updatedAt":     time.Now(),
+	}}}, {{Key: "$unset", Value: "totpPending"}}}
+
+	result, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return common.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoUserRepository) DisableTOTP(id string) error {
+	return r.updateOne(id, bson.M{
+		"$set":   bson.M{"totpEnabled": false, "updatedAt": time.Now()},
+		"$unset": bson.M{"totpSecret": "", "totpPending": "", "totpLastStep": "", "recoveryCodes": ""},
+	})
+}
+
+// AcceptTOTPStep records the time step of an accepted TOTP code. It returns
+// ErrInvalidToken when a code of the same or a later step was accepted before,
+// so that every code works only once.
+func (r *MongoUserRepository) AcceptTOTPStep(id string, step int64) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	filter := bson.M{"_id": objID, "totpLastStep": bson.M{"$not": bson.M{"$gte": step}}}
+	update := bson.M{"$set": bson.M{"totpLastStep": step}}
+
+	result, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return common.ErrInvalidToken
+	}
+
+	return nil
+}
+
+// ConsumeRecoveryCode removes the recovery code hash so that it cannot be used again.
+// It returns ErrInvalidToken when the user has no such code.
+func (r *MongoUserRepository) ConsumeRecoveryCode(id, code string) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	filter := bson.M{"_id": objID, "recoveryCodes": code}
+	update := bson.M{"$pull": bson.M{"recoveryCodes": code}}
+
+	result, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	if result.ModifiedCount == 0 {
+		return common.ErrInvalidToken
+	}
+
+	return nil
+}
+
+func (r *MongoUserRepository) updateOne(id string, update bson.M) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	result, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": objID}, update)
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return common.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// DeleteByID deletes the user together with the tokens, passkeys and OAuth grants issued
+// to them and their login history.
 func (r *MongoUserRepository) DeleteByID(id string) error {
 	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
@@ -165,8 +402,26 @@ func (r *MongoUserRepository) DeleteByID(id string) error {
 	}
 
 	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments // 또는 ErrUserNotFound
+		return common.ErrUserNotFound
 	}
 
+	deleteOneTimeTokens := func(userID string) error {
+		return NewOneTimeTokenRepository().DeleteByUserID(userID, "")
+	}
+
+	for _, deleteByUserID := range []func(userID string) error{
+		NewAccessTokenRepository().DeleteByUserID,
+		deleteOneTimeTokens,
+		NewPasskeyRepository().DeleteByUserID,
+		NewWebAuthnSessionRepository().DeleteByUserID,
+		NewLoginRepository().DeleteByUserID,
+		NewSecurityEventRepository().DeleteByUserID,
+		NewOAuthGrantRepository().DeleteByUserID,
+		NewOAuthCodeRepository().DeleteByUserID,
+	} {
+		if err := deleteByUserID(id); err != nil {
+			return err
+		}
+	}
 	return nil
 }
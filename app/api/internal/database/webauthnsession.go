@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// WebAuthnSession holds the challenge of a registration or login ceremony between
+// its begin and finish requests. Each session can be finished once. UserID is empty
+// for logins, which start before the user is known.
+type WebAuthnSession struct {
+	ID        bson.ObjectID        `bson:"_id,omitempty"`
+	UserID    bson.ObjectID        `bson:"userID,omitempty"`
+	Purpose   string               `bson:"purpose"`
+	Data      webauthn.SessionData `bson:"data"`
+	ExpiresAt time.Time            `bson:"expiresAt"`
+	CreatedAt time.Time            `bson:"createdAt"`
+}
+
+type WebAuthnSessionRepository interface {
+	Create(userID, purpose string, data *webauthn.SessionData, expiresAt time.Time) (*WebAuthnSession, error)
+	Consume(purpose, id string) (*WebAuthnSession, error)
+	DeleteByUserID(userID string) error
+}
+
+type MongoWebAuthnSessionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebAuthnSessionRepository() WebAuthnSessionRepository {
+	return &MongoWebAuthnSessionRepository{
+		collection: mongoDatabase.Collection("webauthnSessions"),
+	}
+}
+
+func (r *MongoWebAuthnSessionRepository) Create(userID, purpose string, data *webauthn.SessionData, expiresAt time.Time) (*WebAuthnSession, error) {
+	var userObjID bson.ObjectID
+	if userID != "" {
+		var err error
+		if userObjID, err = bson.ObjectIDFromHex(userID); err != nil {
+			return nil, common.ErrInvalidUserID
+		}
+	}
+
+	doc := &WebAuthnSession{
+		UserID:    userObjID,
+		Purpose:   purpose,
+		Data:      *data,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	result, err := r.collection.InsertOne(context.Background(), doc)
+	if err != nil {
+		return nil, common.ErrDatabase
+	}
+
+	doc.ID = result.InsertedID.(bson.ObjectID)
+	return doc, nil
+}
+
+// Consume deletes an unexpired session and returns it.
+// It fails with ErrInvalidToken when the session is unknown, expired or already used.
+func (r *MongoWebAuthnSessionRepository) Consume(purpose, id string) (*WebAuthnSession, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, common.ErrInvalidToken
+	}
+
+	filter := bson.M{
+		"_id":       objID,
+		"purpose":   purpose,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+
+	var session WebAuthnSession
+	err = r.collection.FindOneAndDelete(context.Background(), filter).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrInvalidToken
+		}
+		return nil, common.ErrDatabase
+	}
+
+	return &session, nil
+}
+
+func (r *MongoWebAuthnSessionRepository) DeleteByUserID(userID string) error {
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return common.ErrInvalidUserID
+	}
+
+	_, err = r.collection.DeleteMany(context.Background(), bson.M{"userID": userObjID})
+	if err != nil {
+		return common.ErrDatabase
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package export
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"github.com/hwangseonu/paperless.dev/internal/templates"
+)
+
+const (
+	OrderResume = "resume"
+	OrderRecent = "recent"
+)
+
+// dateFormats are the layouts selectable for text based exports.
+var dateFormats = map[string]string{
+	"short":   dateFormat,
+	"long":    "January 2006",
+	"iso":     "2006-01",
+	"numeric": "01/2006",
+	"year":    "2006",
+}
+
+var sectionTitles = map[string]string{
+	templates.SectionSkills:     "Skills",
+	templates.SectionExperience: "Experience",
+	templates.SectionEducation:  "Education",
+	templates.SectionProjects:   "Projects",
+}
+
+// Options controls the text based exports. Sections selects the sections to include,
+// in order; DateFormat is a time layout and Order either keeps entries in the order of
+// the resume or sorts them by start date, most recent first.
+type Options struct {
+	Sections   []string
+	DateFormat string
+	Order      string
+}
+
+// ParseOptions validates export options given as query values. Empty values select the
+// template's sections, the short date format and the resume's order.
+func ParseOptions(template *templates.Template, sections, dateFormatName, order string) (Options, error) {
+	opts := Options{
+		Sections:   template.Sections,
+		DateFormat: dateFormat,
+		Order:      OrderResume,
+	}
+
+	if sections != "" {
+		opts.Sections = nil
+		for _, section := range strings.Split(sections, ",") {
+			section = strings.TrimSpace(section)
+			if _, ok := sectionTitles[section]; !ok {
+				return opts, invalidOption("sections", fmt.Sprintf("unknown section %q", section))
+			}
+			if !slices.Contains(opts.Sections, section) {
+				opts.Sections = append(opts.Sections, section)
+			}
+		}
+	}
+
+	if dateFormatName != "" {
+		layout, ok := dateFormats[dateFormatName]
+		if !ok {
+			return opts, invalidOption("dateFormat", fmt.Sprintf("unknown date format %q", dateFormatName))
+		}
+		opts.DateFormat = layout
+	}
+
+	switch order {
+	case "":
+	case OrderResume, OrderRecent:
+		opts.Order = order
+	default:
+		return opts, invalidOption("order", fmt.Sprintf("unknown order %q", order))
+	}
+
+	return opts, nil
+}
+
+func invalidOption(field, message string) error {
+	return common.NewValidationError(common.ErrInvalidInput, common.FieldError{
+		Field:   field,
+		Reason:  "invalid",
+		Message: message,
+	})
+}
+
+// document is a resume laid out independently of the output format, with dates
+// already formatted and sections in their final order.
+type document struct {
+	Title    string
+	Email    string
+	URL      string
+	Summary  []string
+	Sections []section
+}
+
+type section struct {
+	Name    string
+	Title   string
+	Items   []string
+	Entries []entry
+}
+
+// entry is one experience, education or project. Lines are the lines of its
+// description; those starting with a list marker are bullets.
+type entry struct {
+	Title    string
+	Link     string
+	Subtitle string
+	Meta     string
+	Lines    []string
+	Tags     []string
+}
+
+// datedEntry pairs an entry with the start date it is sorted by.
+type datedEntry struct {
+	entry
+	start time.Time
+}
+
+func newDocument(resume *schema.ResumeResponseSchema, opts Options) *document {
+	doc := &document{
+		Title:   resume.Title,
+		Email:   resume.Email,
+		URL:     resume.URL,
+		Summary: descriptionLines(resume.Description),
+	}
+
+	dates := func(start time.Time, end *time.Time) string {
+		if start.IsZero() {
+			return ""
+		}
+		to := "Present"
+		if end != nil {
+			to = end.Format(opts.DateFormat)
+		}
+		return start.Format(opts.DateFormat) + " – " + to
+	}
+
+	for _, name := range opts.Sections {
+		s := section{Name: name, Title: sectionTitles[name]}
+		var entries []datedEntry
+
+		switch name {
+		case templates.SectionSkills:
+			s.Items = resume.Skills
+		case templates.SectionExperience:
+			for _, experience := range resume.Experiences {
+				entries = append(entries, datedEntry{entry{
+					Title: joinNonEmpty(" · ", experience.Title, experience.Company),
+					Meta:  joinNonEmpty(" · ", dates(experience.StartDate, experience.EndDate), experience.Location),
+					Lines: descriptionLines(experience.Description),
+				}, experience.StartDate})
+			}
+		case templates.SectionEducation:
+			for _, education := range resume.Educations {
+				gpa := ""
+				if education.GPA != "" {
+					gpa = "GPA " + education.GPA
+				}
+				entries = append(entries, datedEntry{entry{
+					Title:    education.School,
+					Subtitle: joinNonEmpty(", ", education.Degree, education.Major),
+					Meta:     joinNonEmpty(" · ", dates(education.StartDate, education.EndDate), gpa),
+					Lines:    descriptionLines(education.Activities),
+				}, education.StartDate})
+			}
+		case templates.SectionProjects:
+			for _, project := range resume.Projects {
+				entries = append(entries, datedEntry{entry{
+					Title: project.Title,
+					Link:  project.URL,
+					Meta:  dates(project.StartDate, project.EndDate),
+					Lines: descriptionLines(project.Description),
+					Tags:  project.Skills,
+				}, project.StartDate})
+			}
+		}
+
+		if opts.Order == OrderRecent {
+			sort.SliceStable(entries, func(i, j int) bool {
+				return entries[i].start.After(entries[j].start)
+			})
+		}
+		for _, e := range entries {
+			s.Entries = append(s.Entries, e.entry)
+		}
+
+		if len(s.Items) > 0 || len(s.Entries) > 0 {
+			doc.Sections = append(doc.Sections, s)
+		}
+	}
+
+	return doc
+}
+
+// descriptionLines splits free text into trimmed, non-empty lines.
+func descriptionLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// bulletText returns line without its "-", "*" or "•" list marker, and whether it had one.
+func bulletText(line string) (string, bool) {
+	for _, marker := range []string{"- ", "* ", "• "} {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(strings.TrimPrefix(line, marker)), true
+		}
+	}
+	return line, false
+}
@@ -0,0 +1,260 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"github.com/hwangseonu/paperless.dev/internal/templates"
+)
+
+// mmToTwips converts millimetres to twentieths of a point, the unit of WordprocessingML
+// page measurements.
+const mmToTwips = 1440 / 25.4
+
+// pageSizes are the page sizes of the built-in templates in twips.
+var pageSizes = map[string][2]int{
+	"A4":     {11906, 16838},
+	"Letter": {12240, 15840},
+}
+
+// RenderDOCX renders resume into a Word document. The layout is a single column of real
+// headings, paragraphs and bullet lists without tables or text boxes, which applicant
+// tracking systems parse reliably. The archive is reproducible for a given resume.
+func RenderDOCX(w io.Writer, resume *schema.ResumeResponseSchema, opts Options) error {
+	template := templates.Resolve(resume.Template)
+	style := StyleFor(template.Name)
+	doc := newDocument(resume, opts)
+
+	body := &docxBody{}
+	body.render(doc)
+
+	modified := resume.UpdatedAt.UTC()
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRootRels},
+		{"docProps/core.xml", docxCore(resume.Title, modified)},
+		{"docProps/app.xml", docxApp},
+		{"word/_rels/document.xml.rels", body.relationships()},
+		{"word/document.xml", body.document(template.PageSize, style.Margin)},
+		{"word/styles.xml", docxStyles(style)},
+		{"word/numbering.xml", docxNumbering},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, part := range parts {
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     part.name,
+			Method:   zip.Deflate,
+			Modified: modified,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// docxBody accumulates the paragraphs of word/document.xml and the relationships of the
+// hyperlinks they contain.
+type docxBody struct {
+	xml   strings.Builder
+	links []string
+}
+
+func (b *docxBody) render(doc *document) {
+	b.paragraph("Title", b.run(doc.Title))
+
+	var contact []string
+	if doc.Email != "" {
+		contact = append(contact, b.hyperlink("mailto:"+doc.Email, doc.Email))
+	}
+	if doc.URL != "" {
+		contact = append(contact, b.hyperlink(doc.URL, doc.URL))
+	}
+	if len(contact) > 0 {
+		b.paragraph("Contact", strings.Join(contact, b.run(" · ")))
+	}
+	b.lines(doc.Summary)
+
+	for _, s := range doc.Sections {
+		b.paragraph("Heading1", b.run(s.Title))
+
+		if len(s.Items) > 0 {
+			b.paragraph("", b.run(strings.Join(s.Items, ", ")))
+		}
+
+		for _, e := range s.Entries {
+			title := b.run(e.Title)
+			if e.Link != "" {
+				title = b.hyperlink(e.Link, e.Title)
+			}
+			b.paragraph("Heading2", title)
+
+			if e.Subtitle != "" {
+				b.paragraph("", b.run(e.Subtitle))
+			}
+			if e.Meta != "" {
+				b.paragraph("Meta", b.run(e.Meta))
+			}
+			b.lines(e.Lines)
+			if len(e.Tags) > 0 {
+				b.paragraph("Meta", b.run(strings.Join(e.Tags, ", ")))
+			}
+		}
+	}
+}
+
+// lines writes description lines as List Bullet or Normal paragraphs.
+func (b *docxBody) lines(lines []string) {
+	for _, line := range lines {
+		if text, isBullet := bulletText(line); isBullet {
+			b.paragraph("ListBullet", b.run(text))
+		} else {
+			b.paragraph("", b.run(line))
+		}
+	}
+}
+
+func (b *docxBody) paragraph(style, runs string) {
+	b.xml.WriteString("<w:p>")
+	if style != "" {
+		b.xml.WriteString(`<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`)
+	}
+	b.xml.WriteString(runs)
+	b.xml.WriteString("</w:p>")
+}
+
+func (b *docxBody) run(text string) string {
+	return `<w:r><w:t xml:space="preserve">` + escapeXML(text) + `</w:t></w:r>`
+}
+
+// hyperlink returns an external link run and records its relationship. Relationship IDs
+// start after the fixed ones for styles and numbering.
+func (b *docxBody) hyperlink(target, text string) string {
+	b.links = append(b.links, target)
+	id := fmt.Sprintf("rId%d", len(b.links)+2)
+	return `<w:hyperlink r:id="` + id + `"><w:r><w:rPr><w:rStyle w:val="Hyperlink"/></w:rPr>` +
+		`<w:t xml:space="preserve">` + escapeXML(text) + `</w:t></w:r></w:hyperlink>`
+}
+
+func (b *docxBody) relationships() string {
+	var rels strings.Builder
+	rels.WriteString(xml.Header)
+	rels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	rels.WriteString(`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	rels.WriteString(`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>`)
+	for i, target := range b.links {
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="%s" TargetMode="External"/>`,
+			i+3, escapeXML(target))
+	}
+	rels.WriteString(`</Relationships>`)
+	return rels.String()
+}
+
+func (b *docxBody) document(pageSize string, margin float64) string {
+	size, ok := pageSizes[pageSize]
+	if !ok {
+		size = pageSizes["A4"]
+	}
+	twips := int(margin * mmToTwips)
+
+	var doc strings.Builder
+	doc.WriteString(xml.Header)
+	doc.WriteString(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>`)
+	doc.WriteString(b.xml.String())
+	fmt.Fprintf(&doc, `<w:sectPr><w:pgSz w:w="%d" w:h="%d"/><w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>`,
+		size[0], size[1], twips, twips, twips, twips)
+	doc.WriteString(`</w:body></w:document>`)
+	return doc.String()
+}
+
+func escapeXML(text string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+func hexColor(c color) string {
+	return fmt.Sprintf("%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// docxStyles defines the paragraph styles used by the document. Sizes are in half points.
+func docxStyles(style Style) string {
+	caps := ""
+	if style.UppercaseHeadings {
+		caps = "<w:caps/>"
+	}
+	rule := ""
+	if style.HeadingRule {
+		rule = `<w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="` + hexColor(style.Accent) + `"/></w:pBdr>`
+	}
+	halfPoints := func(size float64) int { return int(size * 2) }
+
+	return xml.Header + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="Malgun Gothic" w:cs="Calibri"/>` +
+		fmt.Sprintf(`<w:color w:val="%s"/><w:sz w:val="%d"/><w:szCs w:val="%d"/><w:lang w:val="en-US"/></w:rPr></w:rPrDefault>`, hexColor(style.Text), halfPoints(style.BodySize), halfPoints(style.BodySize)) +
+		`<w:pPrDefault><w:pPr><w:spacing w:after="40" w:line="264" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+		`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+		fmt.Sprintf(`<w:pPr><w:spacing w:after="60"/></w:pPr><w:rPr><w:b/><w:color w:val="%s"/><w:sz w:val="%d"/><w:szCs w:val="%d"/></w:rPr></w:style>`, hexColor(style.Accent), halfPoints(style.NameSize), halfPoints(style.NameSize)) +
+		`<w:style w:type="paragraph" w:styleId="Contact"><w:name w:val="Contact"/><w:basedOn w:val="Normal"/><w:qFormat/>` +
+		fmt.Sprintf(`<w:pPr><w:spacing w:after="120"/></w:pPr><w:rPr><w:color w:val="%s"/></w:rPr></w:style>`, hexColor(style.Muted)) +
+		`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:keepNext/>` + rule + `<w:spacing w:before="280" w:after="80"/><w:outlineLvl w:val="0"/></w:pPr>` +
+		fmt.Sprintf(`<w:rPr><w:b/>%s<w:color w:val="%s"/><w:sz w:val="%d"/><w:szCs w:val="%d"/></w:rPr></w:style>`, caps, hexColor(style.Accent), halfPoints(style.HeadingSize), halfPoints(style.HeadingSize)) +
+		`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:keepNext/><w:spacing w:before="160" w:after="20"/><w:outlineLvl w:val="1"/></w:pPr>` +
+		fmt.Sprintf(`<w:rPr><w:b/><w:sz w:val="%d"/><w:szCs w:val="%d"/></w:rPr></w:style>`, halfPoints(style.BodySize+1), halfPoints(style.BodySize+1)) +
+		`<w:style w:type="paragraph" w:styleId="Meta"><w:name w:val="Meta"/><w:basedOn w:val="Normal"/><w:qFormat/>` +
+		fmt.Sprintf(`<w:rPr><w:color w:val="%s"/><w:sz w:val="%d"/><w:szCs w:val="%d"/></w:rPr></w:style>`, hexColor(style.Muted), halfPoints(style.BodySize-1), halfPoints(style.BodySize-1)) +
+		`<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:basedOn w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr></w:style>` +
+		`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/>` +
+		fmt.Sprintf(`<w:rPr><w:color w:val="%s"/><w:u w:val="single"/></w:rPr></w:style>`, hexColor(style.Accent)) +
+		`</w:styles>`
+}
+
+func docxCore(title string, modified time.Time) string {
+	date := modified.Format(time.RFC3339)
+	return xml.Header + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+		`<dc:title>` + escapeXML(title) + `</dc:title><dc:creator>paperless.dev</dc:creator>` +
+		`<dcterms:created xsi:type="dcterms:W3CDTF">` + date + `</dcterms:created>` +
+		`<dcterms:modified xsi:type="dcterms:W3CDTF">` + date + `</dcterms:modified>` +
+		`</cp:coreProperties>`
+}
+
+const docxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+	`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+	`<Override PartName="/docProps/app.xml" ContentType="application/vnd.openxmlformats-officedocument.extended-properties+xml"/>` +
+	`</Types>`
+
+const docxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+	`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties" Target="docProps/app.xml"/>` +
+	`</Relationships>`
+
+const docxApp = xml.Header + `<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Application>paperless.dev</Application></Properties>`
+
+const docxNumbering = xml.Header + `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:abstractNum w:abstractNumId="0"><w:multiLevelType w:val="singleLevel"/>` +
+	`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="•"/><w:lvlJc w:val="left"/>` +
+	`<w:pPr><w:ind w:left="360" w:hanging="240"/></w:pPr></w:lvl></w:abstractNum>` +
+	`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>` +
+	`</w:numbering>`
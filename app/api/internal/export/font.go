@@ -0,0 +1,75 @@
+package export
+
+import (
+	_ "embed"
+	"sync"
+	"unicode"
+
+	"github.com/go-fonts/dejavu/dejavusans"
+	"golang.org/x/image/font/sfnt"
+)
+
+// The fallback fonts are subsets of Noto Sans CJK covering Korean text. They are only
+// added to documents that contain characters DejaVu Sans cannot draw, because parsing
+// them is comparatively slow.
+var (
+	//go:embed fonts/NotoSansCJKkr-Regular-Subset.ttf
+	fallbackRegularTTF []byte
+	//go:embed fonts/NotoSansCJKkr-Bold-Subset.ttf
+	fallbackBoldTTF []byte
+)
+
+const (
+	fontSans     = "sans"
+	fontFallback = "fallback"
+)
+
+var sansFont = sync.OnceValue(func() *sfnt.Font {
+	font, err := sfnt.Parse(dejavusans.TTF)
+	if err != nil {
+		panic(err)
+	}
+	return font
+})
+
+// textRun is a piece of text drawn with a single font.
+type textRun struct {
+	text     string
+	fallback bool
+}
+
+// hasGlyph reports whether DejaVu Sans can draw r.
+func hasGlyph(r rune) bool {
+	if r < unicode.MaxASCII {
+		return true
+	}
+
+	var buf sfnt.Buffer
+	index, err := sansFont().GlyphIndex(&buf, r)
+	return err == nil && index != 0
+}
+
+// splitRuns splits text into runs of DejaVu Sans and fallback characters. Spaces stay in
+// the current run so that mixed text does not switch fonts on every word boundary.
+func splitRuns(text string) []textRun {
+	var runs []textRun
+	var current []rune
+	fallback := false
+
+	for _, r := range text {
+		needsFallback := !hasGlyph(r)
+		if len(current) > 0 && needsFallback != fallback && !unicode.IsSpace(r) {
+			runs = append(runs, textRun{text: string(current), fallback: fallback})
+			current = current[:0]
+		}
+		if len(current) == 0 || !unicode.IsSpace(r) {
+			fallback = needsFallback
+		}
+		current = append(current, r)
+	}
+
+	if len(current) > 0 {
+		runs = append(runs, textRun{text: string(current), fallback: fallback})
+	}
+	return runs
+}
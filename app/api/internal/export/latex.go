@@ -0,0 +1,234 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"github.com/hwangseonu/paperless.dev/internal/templates"
+)
+
+// paperlessClass implements the moderncv commands used by RenderLaTeX with standard
+// packages only. It is shipped in the bundle for installations without moderncv.
+//
+//go:embed latex/paperlesscv.cls
+var paperlessClass []byte
+
+// latexStyle selects the moderncv style and color of a template.
+type latexStyle struct {
+	Style string
+	Color string
+}
+
+var latexStyles = map[string]latexStyle{
+	"modern":  {"classic", "blue"},
+	"classic": {"classic", "black"},
+	"minimal": {"banking", "grey"},
+}
+
+var latexPaper = map[string]string{
+	"A4":     "a4paper",
+	"Letter": "letterpaper",
+}
+
+// latexEscaper escapes the characters that are special in LaTeX text.
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	"{", `\{`,
+	"}", `\}`,
+	"&", `\&`,
+	"%", `\%`,
+	"$", `\$`,
+	"#", `\#`,
+	"_", `\_`,
+	"~", `\textasciitilde{}`,
+	"^", `\textasciicircum{}`,
+	"<", `\textless{}`,
+	">", `\textgreater{}`,
+)
+
+// latexURLEscaper escapes the characters hyperref does not accept verbatim in URLs.
+var latexURLEscaper = strings.NewReplacer(
+	`\`, "%5C",
+	"{", "%7B",
+	"}", "%7D",
+	"%", `\%`,
+	"#", `\#`,
+)
+
+// RenderLaTeX renders resume as a LaTeX source for the moderncv class, with the style and
+// color of its template. Compile it with pdflatex, or with xelatex or lualatex for
+// scripts the default fonts do not cover.
+func RenderLaTeX(w io.Writer, resume *schema.ResumeResponseSchema, opts Options) error {
+	return renderLaTeX(w, resume, opts, "moderncv")
+}
+
+// RenderLaTeXBundle renders a zip with the LaTeX source, using the bundled paperlesscv
+// class instead of moderncv, and the class file itself.
+func RenderLaTeXBundle(w io.Writer, resume *schema.ResumeResponseSchema, opts Options) error {
+	var source bytes.Buffer
+	if err := renderLaTeX(&source, resume, opts, "paperlesscv"); err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, file := range []struct {
+		name    string
+		content []byte
+	}{
+		{"resume.tex", source.Bytes()},
+		{"paperlesscv.cls", paperlessClass},
+	} {
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: resume.UpdatedAt.UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(file.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func renderLaTeX(w io.Writer, resume *schema.ResumeResponseSchema, opts Options, class string) error {
+	template := templates.Resolve(resume.Template)
+	style, ok := latexStyles[template.Name]
+	if !ok {
+		style = latexStyles[templates.Default]
+	}
+	paper, ok := latexPaper[template.PageSize]
+	if !ok {
+		paper = latexPaper["A4"]
+	}
+	doc := newDocument(resume, opts)
+
+	var out strings.Builder
+	out.WriteString(`\documentclass[11pt,` + paper + `,sans]{` + class + "}\n")
+	out.WriteString(`\moderncvstyle{` + style.Style + "}\n")
+	out.WriteString(`\moderncvcolor{` + style.Color + "}\n")
+	out.WriteString(`\usepackage{iftex}` + "\n")
+	out.WriteString(`\ifPDFTeX` + "\n")
+	out.WriteString(`  \usepackage[T1]{fontenc}` + "\n")
+	out.WriteString(`  \usepackage[utf8]{inputenc}` + "\n")
+	out.WriteString(`\fi` + "\n")
+	out.WriteString(`\usepackage[scale=0.8]{geometry}` + "\n\n")
+
+	out.WriteString(`\name{` + latexText(doc.Title) + "}{}\n")
+
+	// moderncv's \email and \homepage print their argument and prepend http:// to
+	// links, so contact details are written as ready-made links instead.
+	var contact []string
+	if doc.Email != "" {
+		contact = append(contact, `\href{mailto:`+latexURL(doc.Email)+`}{`+latexText(doc.Email)+`}`)
+	}
+	if doc.URL != "" {
+		contact = append(contact, `\href{`+latexURL(doc.URL)+`}{`+latexText(doc.URL)+`}`)
+	}
+	if len(contact) > 0 {
+		out.WriteString(`\extrainfo{` + strings.Join(contact, ` \textperiodcentered{} `) + "}\n")
+	}
+
+	out.WriteString("\n" + `\begin{document}` + "\n")
+	out.WriteString(`\makecvtitle` + "\n")
+
+	if len(doc.Summary) > 0 {
+		out.WriteString("\n" + `\cvitem{}{` + latexLines(doc.Summary) + "}\n")
+	}
+
+	for _, s := range doc.Sections {
+		out.WriteString("\n" + `\section{` + latexText(s.Title) + "}\n")
+
+		if len(s.Items) > 0 {
+			out.WriteString(`\cvitem{}{` + latexText(strings.Join(s.Items, ", ")) + "}\n")
+		}
+
+		for _, e := range s.Entries {
+			title := latexText(e.Title)
+			if e.Link != "" {
+				title = `\href{` + latexURL(e.Link) + `}{` + title + `}`
+			}
+
+			description := latexLines(e.Lines)
+			if len(e.Tags) > 0 {
+				tags := `\textit{` + latexText(strings.Join(e.Tags, ", ")) + `}`
+				if description != "" {
+					tags = `\par ` + tags
+				}
+				description += tags
+			}
+
+			out.WriteString(`\cventry{` + latexText(e.Meta) + `}{` + title + `}{` + latexText(e.Subtitle) + "}{}{}{" + description + "}\n")
+		}
+	}
+
+	out.WriteString("\n" + `\end{document}` + "\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// latexText escapes text for use in a paragraph. Control characters are dropped since
+// TeX would otherwise read them as input.
+func latexText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	return latexEscaper.Replace(text)
+}
+
+// latexURL escapes url for the first argument of \href.
+func latexURL(url string) string {
+	return latexURLEscaper.Replace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, url))
+}
+
+// latexLines renders description lines, grouping bullet lines into itemize lists and
+// separating other lines with line breaks.
+func latexLines(lines []string) string {
+	var out strings.Builder
+	inList := false
+
+	for _, line := range lines {
+		text, isBullet := bulletText(line)
+		switch {
+		case isBullet && !inList:
+			out.WriteString("\n" + `\begin{itemize}` + "\n")
+		case !isBullet && inList:
+			out.WriteString(`\end{itemize}` + "\n")
+		case !isBullet && out.Len() > 0:
+			out.WriteString(`\newline` + "\n")
+		}
+		inList = isBullet
+
+		text = latexText(text)
+		if isBullet {
+			// An opening bracket right after \item would be read as its optional argument.
+			if strings.HasPrefix(text, "[") {
+				text = "{}" + text
+			}
+			out.WriteString(`\item ` + text + "\n")
+		} else {
+			out.WriteString(text)
+		}
+	}
+	if inList {
+		out.WriteString(`\end{itemize}` + "\n")
+	}
+
+	return strings.TrimSpace(out.String())
+}
@@ -0,0 +1,310 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-fonts/dejavu/dejavusans"
+	"github.com/go-fonts/dejavu/dejavusansbold"
+	"github.com/go-pdf/fpdf"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"github.com/hwangseonu/paperless.dev/internal/templates"
+)
+
+type color struct {
+	R, G, B int
+}
+
+// Style controls how a template looks in the PDF export. The page size and the
+// sections to render come from the template registry.
+type Style struct {
+	Margin            float64
+	NameSize          float64
+	HeadingSize       float64
+	BodySize          float64
+	Accent            color
+	Text              color
+	Muted             color
+	UppercaseHeadings bool
+	HeadingRule       bool
+}
+
+var styles = map[string]Style{
+	"modern": {
+		Margin:      18,
+		NameSize:    22,
+		HeadingSize: 13,
+		BodySize:    10,
+		Accent:      color{37, 99, 235},
+		Text:        color{17, 24, 39},
+		Muted:       color{107, 114, 128},
+		HeadingRule: true,
+	},
+	"classic": {
+		Margin:            22,
+		NameSize:          20,
+		HeadingSize:       12,
+		BodySize:          10.5,
+		Accent:            color{0, 0, 0},
+		Text:              color{0, 0, 0},
+		Muted:             color{80, 80, 80},
+		UppercaseHeadings: true,
+		HeadingRule:       true,
+	},
+	"minimal": {
+		Margin:      25,
+		NameSize:    18,
+		HeadingSize: 11,
+		BodySize:    9.5,
+		Accent:      color{55, 65, 81},
+		Text:        color{31, 41, 55},
+		Muted:       color{156, 163, 175},
+	},
+}
+
+// StyleFor returns the style of template, or the default template's style when the
+// name is unknown.
+func StyleFor(template string) Style {
+	if style, ok := styles[template]; ok {
+		return style
+	}
+	return styles[templates.Default]
+}
+
+const dateFormat = "Jan 2006"
+
+// pointsToMM converts a font size to the document unit.
+const pointsToMM = 25.4 / 72
+
+type pdfRenderer struct {
+	pdf   *fpdf.Fpdf
+	style Style
+	// fallbackStyles are the styles of the fallback font added so far.
+	fallbackStyles map[string]bool
+}
+
+// RenderPDF renders resume into a paginated PDF using its template. Text is embedded
+// with subsetted TrueType fonts, so it stays selectable and searchable.
+func RenderPDF(w io.Writer, resume *schema.ResumeResponseSchema) error {
+	template := templates.Resolve(resume.Template)
+	style := StyleFor(template.Name)
+
+	pdf := fpdf.New("P", "mm", template.PageSize, "")
+	pdf.SetMargins(style.Margin, style.Margin, style.Margin)
+	pdf.SetAutoPageBreak(true, style.Margin)
+	pdf.SetTitle(resume.Title, true)
+	pdf.SetCreator("paperless.dev", true)
+	pdf.SetCreationDate(resume.UpdatedAt)
+	pdf.SetModificationDate(resume.UpdatedAt)
+	pdf.AddUTF8FontFromBytes(fontSans, "", dejavusans.TTF)
+	pdf.AddUTF8FontFromBytes(fontSans, "B", dejavusansbold.TTF)
+	pdf.AliasNbPages("")
+
+	r := &pdfRenderer{pdf: pdf, style: style, fallbackStyles: map[string]bool{}}
+	pdf.SetFooterFunc(r.footer)
+	pdf.AddPage()
+
+	r.header(resume)
+	for _, section := range template.Sections {
+		switch section {
+		case templates.SectionSkills:
+			r.skills(resume.Skills)
+		case templates.SectionExperience:
+			r.experiences(resume.Experiences)
+		case templates.SectionEducation:
+			r.educations(resume.Educations)
+		case templates.SectionProjects:
+			r.projects(resume.Projects)
+		}
+	}
+
+	return pdf.Output(w)
+}
+
+func (r *pdfRenderer) header(resume *schema.ResumeResponseSchema) {
+	r.paragraph(resume.Title, r.style.NameSize, true, r.style.Accent)
+	r.paragraph(joinNonEmpty(" · ", resume.Email, resume.URL), r.style.BodySize, false, r.style.Muted)
+
+	if resume.Description != "" {
+		r.pdf.Ln(2)
+		r.description(resume.Description)
+	}
+}
+
+func (r *pdfRenderer) skills(skills []string) {
+	if len(skills) == 0 {
+		return
+	}
+
+	r.heading("Skills")
+	r.paragraph(strings.Join(skills, ", "), r.style.BodySize, false, r.style.Text)
+}
+
+func (r *pdfRenderer) experiences(experiences []schema.ExperienceResponseSchema) {
+	if len(experiences) == 0 {
+		return
+	}
+
+	r.heading("Experience")
+	for i, experience := range experiences {
+		r.entryGap(i)
+		r.paragraph(joinNonEmpty(" · ", experience.Title, experience.Company), r.style.BodySize+1, true, r.style.Text)
+		r.paragraph(joinNonEmpty(" · ", dateRange(experience.StartDate, experience.EndDate), experience.Location), r.style.BodySize-1, false, r.style.Muted)
+		r.description(experience.Description)
+	}
+}
+
+func (r *pdfRenderer) educations(educations []schema.EducationResponseSchema) {
+	if len(educations) == 0 {
+		return
+	}
+
+	r.heading("Education")
+	for i, education := range educations {
+		r.entryGap(i)
+		r.paragraph(education.School, r.style.BodySize+1, true, r.style.Text)
+		r.paragraph(joinNonEmpty(", ", education.Degree, education.Major), r.style.BodySize, false, r.style.Text)
+
+		gpa := ""
+		if education.GPA != "" {
+			gpa = "GPA " + education.GPA
+		}
+		r.paragraph(joinNonEmpty(" · ", dateRange(education.StartDate, education.EndDate), gpa), r.style.BodySize-1, false, r.style.Muted)
+		r.description(education.Activities)
+	}
+}
+
+func (r *pdfRenderer) projects(projects []schema.ProjectResponseSchema) {
+	if len(projects) == 0 {
+		return
+	}
+
+	r.heading("Projects")
+	for i, project := range projects {
+		r.entryGap(i)
+		r.paragraph(project.Title, r.style.BodySize+1, true, r.style.Text)
+		r.paragraph(joinNonEmpty(" · ", dateRange(project.StartDate, project.EndDate), project.URL), r.style.BodySize-1, false, r.style.Muted)
+		r.description(project.Description)
+		if len(project.Skills) > 0 {
+			r.paragraph(strings.Join(project.Skills, ", "), r.style.BodySize-1, false, r.style.Muted)
+		}
+	}
+}
+
+// heading starts a section, moving to a new page first when the heading would
+// otherwise be left alone at the bottom of the current one.
+func (r *pdfRenderer) heading(title string) {
+	_, pageHeight := r.pdf.GetPageSize()
+	if r.pdf.GetY() > pageHeight-r.style.Margin-30 {
+		r.pdf.AddPage()
+	} else {
+		r.pdf.Ln(5)
+	}
+
+	if r.style.UppercaseHeadings {
+		title = strings.ToUpper(title)
+	}
+	r.paragraph(title, r.style.HeadingSize, true, r.style.Accent)
+
+	if r.style.HeadingRule {
+		pageWidth, _ := r.pdf.GetPageSize()
+		y := r.pdf.GetY() + 0.5
+		r.pdf.SetDrawColor(r.style.Accent.R, r.style.Accent.G, r.style.Accent.B)
+		r.pdf.SetLineWidth(0.3)
+		r.pdf.Line(r.style.Margin, y, pageWidth-r.style.Margin, y)
+		r.pdf.Ln(2)
+	}
+	r.pdf.Ln(1)
+}
+
+func (r *pdfRenderer) entryGap(i int) {
+	if i > 0 {
+		r.pdf.Ln(3)
+	}
+}
+
+// description renders free text line by line. Lines starting with "-", "*" or "•"
+// become bullet points.
+func (r *pdfRenderer) description(text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimSpace(line)
+		for _, marker := range []string{"- ", "* ", "• "} {
+			if strings.HasPrefix(line, marker) {
+				line = "• " + strings.TrimSpace(strings.TrimPrefix(line, marker))
+				break
+			}
+		}
+		r.paragraph(line, r.style.BodySize, false, r.style.Text)
+	}
+}
+
+// paragraph writes text and ends the line. Characters DejaVu Sans cannot draw are
+// written with the fallback font in the same weight.
+func (r *pdfRenderer) paragraph(text string, size float64, bold bool, c color) {
+	if text == "" {
+		return
+	}
+
+	lineHeight := size * pointsToMM * 1.35
+	r.pdf.SetTextColor(c.R, c.G, c.B)
+
+	style := ""
+	if bold {
+		style = "B"
+	}
+
+	for _, run := range splitRuns(text) {
+		if run.fallback {
+			r.useFallback(style)
+			r.pdf.SetFont(fontFallback, style, size)
+		} else {
+			r.pdf.SetFont(fontSans, style, size)
+		}
+		r.pdf.Write(lineHeight, run.text)
+	}
+	r.pdf.Ln(lineHeight)
+}
+
+func (r *pdfRenderer) useFallback(style string) {
+	if r.fallbackStyles[style] {
+		return
+	}
+
+	ttf := fallbackRegularTTF
+	if style == "B" {
+		ttf = fallbackBoldTTF
+	}
+	r.pdf.AddUTF8FontFromBytes(fontFallback, style, ttf)
+	r.fallbackStyles[style] = true
+}
+
+func (r *pdfRenderer) footer() {
+	r.pdf.SetY(-r.style.Margin + 5)
+	r.pdf.SetFont(fontSans, "", 8)
+	r.pdf.SetTextColor(r.style.Muted.R, r.style.Muted.G, r.style.Muted.B)
+	r.pdf.CellFormat(0, 5, fmt.Sprintf("%d / {nb}", r.pdf.PageNo()), "", 0, "C", false, 0, "")
+}
+
+func dateRange(start time.Time, end *time.Time) string {
+	if start.IsZero() {
+		return ""
+	}
+
+	to := "Present"
+	if end != nil {
+		to = end.Format(dateFormat)
+	}
+	return start.Format(dateFormat) + " – " + to
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,189 @@
+package export
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+// markdownEscaper escapes characters that would otherwise start emphasis, links, code
+// or HTML anywhere in a line.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"|", `\|`,
+)
+
+// RenderMarkdown renders resume as CommonMark. The output depends only on the resume and
+// opts, so exports kept in git produce meaningful diffs.
+func RenderMarkdown(w io.Writer, resume *schema.ResumeResponseSchema, opts Options) error {
+	doc := newDocument(resume, opts)
+	out := bufio.NewWriter(w)
+
+	out.WriteString("# " + markdownText(doc.Title) + "\n")
+
+	var contact []string
+	if doc.Email != "" {
+		contact = append(contact, "["+markdownText(doc.Email)+"](mailto:"+markdownURL(doc.Email)+")")
+	}
+	if doc.URL != "" {
+		contact = append(contact, "<"+markdownURL(doc.URL)+">")
+	}
+	if len(contact) > 0 {
+		out.WriteString("\n" + strings.Join(contact, " · ") + "\n")
+	}
+	if len(doc.Summary) > 0 {
+		out.WriteString("\n")
+		writeMarkdownLines(out, doc.Summary)
+	}
+
+	for _, s := range doc.Sections {
+		out.WriteString("\n## " + s.Title + "\n")
+
+		if len(s.Items) > 0 {
+			out.WriteString("\n" + markdownText(strings.Join(s.Items, ", ")) + "\n")
+		}
+
+		for _, e := range s.Entries {
+			title := markdownText(e.Title)
+			if e.Link != "" {
+				title = "[" + title + "](" + markdownURL(e.Link) + ")"
+			}
+			out.WriteString("\n### " + title + "\n")
+
+			details := make([]string, 0, 3)
+			if e.Subtitle != "" {
+				details = append(details, markdownText(e.Subtitle))
+			}
+			if e.Meta != "" {
+				details = append(details, "*"+markdownText(e.Meta)+"*")
+			}
+			if len(e.Tags) > 0 {
+				details = append(details, "*"+markdownText(strings.Join(e.Tags, ", "))+"*")
+			}
+			if len(details) > 0 {
+				out.WriteString("\n" + strings.Join(details, "  \n") + "\n")
+			}
+
+			if len(e.Lines) > 0 {
+				out.WriteString("\n")
+				writeMarkdownLines(out, e.Lines)
+			}
+		}
+	}
+
+	return out.Flush()
+}
+
+// writeMarkdownLines writes description lines as list items and paragraphs, with a blank
+// line wherever a list and a paragraph meet.
+func writeMarkdownLines(out *bufio.Writer, lines []string) {
+	inList := false
+	for i, line := range lines {
+		text, isBullet := bulletText(line)
+		if i > 0 && (isBullet != inList || !isBullet) {
+			out.WriteString("\n")
+		}
+		inList = isBullet
+
+		if isBullet {
+			out.WriteString("- " + markdownLine(text) + "\n")
+		} else {
+			out.WriteString(markdownLine(text) + "\n")
+		}
+	}
+}
+
+func markdownText(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// markdownLine escapes text that would otherwise be read as a heading, list item,
+// quote or thematic break because of how it starts.
+func markdownLine(text string) string {
+	text = markdownText(text)
+	if text == "" {
+		return text
+	}
+
+	switch text[0] {
+	case '#', '+', '-', '=':
+		return `\` + text
+	}
+
+	digits := len(text) - len(strings.TrimLeft(text, "0123456789"))
+	if digits > 0 && digits < len(text) && (text[digits] == '.' || text[digits] == ')') {
+		return text[:digits] + `\` + text[digits:]
+	}
+	return text
+}
+
+// markdownURL percent-encodes characters that would end a link destination.
+func markdownURL(url string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(url)
+}
+
+// RenderText renders resume as plain text for email bodies, with underlined headings
+// and indented bullets. Like RenderMarkdown, the output is deterministic.
+func RenderText(w io.Writer, resume *schema.ResumeResponseSchema, opts Options) error {
+	doc := newDocument(resume, opts)
+	out := bufio.NewWriter(w)
+
+	out.WriteString(underline(doc.Title, "="))
+	if contact := joinNonEmpty(" · ", doc.Email, doc.URL); contact != "" {
+		out.WriteString(contact + "\n")
+	}
+	if len(doc.Summary) > 0 {
+		out.WriteString("\n")
+		writeTextLines(out, doc.Summary, "")
+	}
+
+	for _, s := range doc.Sections {
+		out.WriteString("\n" + underline(strings.ToUpper(s.Title), "-"))
+
+		if len(s.Items) > 0 {
+			out.WriteString(strings.Join(s.Items, ", ") + "\n")
+		}
+
+		for i, e := range s.Entries {
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			out.WriteString(e.Title + "\n")
+			if e.Link != "" {
+				out.WriteString(e.Link + "\n")
+			}
+			for _, line := range []string{e.Subtitle, e.Meta, strings.Join(e.Tags, ", ")} {
+				if line != "" {
+					out.WriteString(line + "\n")
+				}
+			}
+			writeTextLines(out, e.Lines, "  ")
+		}
+	}
+
+	return out.Flush()
+}
+
+func writeTextLines(out *bufio.Writer, lines []string, indent string) {
+	for _, line := range lines {
+		if text, isBullet := bulletText(line); isBullet {
+			out.WriteString(indent + "- " + text + "\n")
+		} else {
+			out.WriteString(indent + line + "\n")
+		}
+	}
+}
+
+func underline(text, char string) string {
+	return text + "\n" + strings.Repeat(char, utf8.RuneCountInString(text)) + "\n"
+}
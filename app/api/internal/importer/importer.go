@@ -0,0 +1,85 @@
+// Package importer turns resumes exported by other tools into new resumes.
+package importer
+
+import (
+	"context"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/jsonresume"
+	"github.com/hwangseonu/paperless.dev/internal/linkedin"
+	"github.com/hwangseonu/paperless.dev/internal/pdfresume"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+const (
+	FormatJSONResume = "jsonresume"
+	FormatLinkedIn   = "linkedin"
+	FormatPDF        = "pdf"
+)
+
+// Result is an imported resume that has not been saved yet, with the data that was
+// left out of it and the rows that could not be read. Formats that are read from the
+// layout of a document report how confident each extracted field is, keyed by its
+// JSON path.
+type Result struct {
+	Format     string
+	Resume     *database.Resume
+	Warnings   []schema.ImportWarning
+	Errors     []schema.ImportError
+	Confidence map[string]float64
+}
+
+// Detect guesses the format of an uploaded file from its content.
+func Detect(data []byte) (string, bool) {
+	if jsonresume.Detect(data) {
+		return FormatJSONResume, true
+	}
+	if linkedin.Detect(data) {
+		return FormatLinkedIn, true
+	}
+	if pdfresume.Detect(data) {
+		return FormatPDF, true
+	}
+	return "", false
+}
+
+// Import parses data in format. An empty format is detected from the content. Reading
+// a PDF file stops when ctx is done.
+func Import(ctx context.Context, format string, data []byte) (*Result, error) {
+	if format == "" {
+		detected, ok := Detect(data)
+		if !ok {
+			return nil, common.ErrUnsupportedFormat
+		}
+		format = detected
+	}
+
+	result := &Result{Format: format}
+
+	var err error
+	switch format {
+	case FormatJSONResume:
+		result.Resume, result.Warnings, err = jsonresume.Decode(data)
+	case FormatLinkedIn:
+		result.Resume, result.Warnings, result.Errors, err = linkedin.Decode(data)
+	case FormatPDF:
+		result.Resume, result.Warnings, result.Confidence, err = pdfresume.Decode(ctx, data)
+	default:
+		return nil, common.ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Warnings == nil {
+		result.Warnings = make([]schema.ImportWarning, 0)
+	}
+	if result.Errors == nil {
+		result.Errors = make([]schema.ImportError, 0)
+	}
+	if result.Confidence == nil {
+		result.Confidence = make(map[string]float64)
+	}
+	return result, nil
+}
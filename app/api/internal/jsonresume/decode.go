@@ -0,0 +1,266 @@
+package jsonresume
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// untitled is the title of imported resumes without basics.name.
+const untitled = "Imported resume"
+
+// supported lists the keys Decode maps, per object. Keys of meta are ignored because
+// they describe the file rather than the resume.
+var supported = map[string][]string{
+	"":          {"$schema", "basics", "work", "education", "projects", "skills", "meta"},
+	"basics":    {"name", "email", "url", "image", "summary"},
+	"work":      {"name", "position", "location", "startDate", "endDate", "summary", "highlights"},
+	"education": {"institution", "studyType", "area", "startDate", "endDate", "score", "activities"},
+	"projects":  {"name", "description", "url", "startDate", "endDate", "highlights", "keywords"},
+	"skills":    {"name"},
+}
+
+var dateLayouts = []string{isoDate, "2006-01", "2006", time.RFC3339}
+
+// Detect reports whether data looks like a JSON Resume: a JSON object with a
+// jsonresume $schema or at least one of the sections Decode maps.
+func Detect(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return false
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+
+	var schemaURL string
+	_ = json.Unmarshal(doc["$schema"], &schemaURL)
+	if strings.Contains(schemaURL, "jsonresume") {
+		return true
+	}
+
+	for _, key := range []string{"basics", "work", "education", "projects", "skills"} {
+		if _, ok := doc[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Decode maps a JSON Resume onto a new resume without an owner. Fields that have no
+// equivalent, and dates that cannot be parsed, are left out and reported as warnings.
+func Decode(data []byte) (*database.Resume, []schema.ImportWarning, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, decodeError(data, err)
+	}
+
+	var doc schema.JSONResume
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, decodeError(data, err)
+	}
+
+	d := &decoder{}
+	d.unmapped("", "", raw)
+
+	resume := &database.Resume{
+		Title:       doc.Basics.Name,
+		Description: doc.Basics.Summary,
+		Email:       doc.Basics.Email,
+		URL:         doc.Basics.URL,
+		Image:       doc.Basics.Image,
+	}
+	if resume.Title == "" {
+		resume.Title = untitled
+	}
+
+	for i, work := range doc.Work {
+		field := fmt.Sprintf("work[%d]", i)
+		resume.Experiences = append(resume.Experiences, database.Experience{
+			ID:          bson.NewObjectID(),
+			Company:     work.Name,
+			Title:       work.Position,
+			Location:    work.Location,
+			StartDate:   d.startDate(field+".startDate", work.StartDate),
+			EndDate:     d.endDate(field+".endDate", work.EndDate),
+			Description: joinHighlights(work.Summary, work.Highlights),
+		})
+	}
+
+	for i, education := range doc.Education {
+		field := fmt.Sprintf("education[%d]", i)
+		resume.Educations = append(resume.Educations, database.Education{
+			ID:         bson.NewObjectID(),
+			School:     education.Institution,
+			Degree:     education.StudyType,
+			Major:      education.Area,
+			StartDate:  d.startDate(field+".startDate", education.StartDate),
+			EndDate:    d.endDate(field+".endDate", education.EndDate),
+			GPA:        education.Score,
+			Activities: education.Activities,
+		})
+	}
+
+	for i, project := range doc.Projects {
+		field := fmt.Sprintf("projects[%d]", i)
+		resume.Projects = append(resume.Projects, database.Project{
+			ID:          bson.NewObjectID(),
+			Title:       project.Name,
+			Description: joinHighlights(project.Description, project.Highlights),
+			URL:         project.URL,
+			StartDate:   d.startDate(field+".startDate", project.StartDate),
+			EndDate:     d.endDate(field+".endDate", project.EndDate),
+			Skills:      project.Keywords,
+		})
+	}
+
+	for _, skill := range doc.Skills {
+		if skill.Name != "" {
+			resume.Skills = append(resume.Skills, skill.Name)
+		}
+	}
+
+	return resume, d.warnings, nil
+}
+
+type decoder struct {
+	warnings []schema.ImportWarning
+}
+
+func (d *decoder) warn(field, message string) {
+	d.warnings = append(d.warnings, schema.ImportWarning{Field: field, Message: message})
+}
+
+// unmapped warns about every non-empty key of object that Decode does not map. section
+// selects the supported keys and prefix is the path of object in the document. Keys are
+// visited in sorted order so the warnings are stable.
+func (d *decoder) unmapped(section, prefix string, object map[string]any) {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+
+		if !slices.Contains(supported[section], key) {
+			if !isEmpty(object[key]) {
+				d.warn(field, "not supported, ignored")
+			}
+			continue
+		}
+		if section != "" || key == "meta" {
+			continue
+		}
+
+		switch value := object[key].(type) {
+		case map[string]any:
+			d.unmapped(key, key, value)
+		case []any:
+			for i, item := range value {
+				if entry, ok := item.(map[string]any); ok {
+					d.unmapped(key, fmt.Sprintf("%s[%d]", key, i), entry)
+				}
+			}
+		}
+	}
+}
+
+func (d *decoder) startDate(field, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	t, ok := parseDate(value)
+	if !ok {
+		d.warn(field, fmt.Sprintf("invalid date %q, ignored", value))
+	}
+	return t
+}
+
+// endDate returns nil for ongoing entries, which are written without an end date or,
+// by some tools, as "present".
+func (d *decoder) endDate(field, value string) *time.Time {
+	if value == "" || strings.EqualFold(value, "present") {
+		return nil
+	}
+
+	t, ok := parseDate(value)
+	if !ok {
+		d.warn(field, fmt.Sprintf("invalid date %q, ignored", value))
+		return nil
+	}
+	return &t
+}
+
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// decodeError reports a JSON syntax or type error on the uploaded file, with the line
+// and column of syntax errors.
+func decodeError(data []byte, err error) error {
+	field := common.FieldError{Field: "file", Reason: "syntax", Message: err.Error()}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		field.Line, field.Column = position(data, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		field.Reason = "type"
+		field.Message = fmt.Sprintf("%s must be %s, not %s", typeErr.Field, typeErr.Type, typeErr.Value)
+		if typeErr.Field == "" {
+			field.Message = "document must be a JSON object, not " + typeErr.Value
+		}
+		field.Line, field.Column = position(data, typeErr.Offset)
+	}
+
+	return common.NewValidationError(common.ErrInvalidImport, field)
+}
+
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
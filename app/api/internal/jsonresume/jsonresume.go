@@ -0,0 +1,134 @@
+// Package jsonresume converts resumes to and from the jsonresume.org schema. Supported
+// fields survive a round trip unchanged; everything else is reported when importing.
+package jsonresume
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+const (
+	SchemaURL = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+	version   = "v1.0.0"
+	isoDate   = "2006-01-02"
+)
+
+// highlightMarker prefixes description lines exported as highlights.
+const highlightMarker = "- "
+
+// Encode converts resume to a JSON Resume. canonical is the public URL of the resume
+// and may be empty.
+func Encode(resume *schema.ResumeResponseSchema, canonical string) *schema.JSONResume {
+	doc := &schema.JSONResume{
+		Schema: SchemaURL,
+		Basics: schema.JSONResumeBasics{
+			Name:    resume.Title,
+			Email:   resume.Email,
+			URL:     resume.URL,
+			Image:   resume.Image,
+			Summary: resume.Description,
+		},
+		Meta: &schema.JSONResumeMeta{
+			Canonical:    canonical,
+			Version:      version,
+			LastModified: resume.UpdatedAt.UTC().Format(time.RFC3339),
+		},
+	}
+
+	for _, experience := range resume.Experiences {
+		summary, highlights := splitHighlights(experience.Description)
+		doc.Work = append(doc.Work, schema.JSONResumeWork{
+			Name:       experience.Company,
+			Position:   experience.Title,
+			Location:   experience.Location,
+			StartDate:  formatDate(experience.StartDate),
+			EndDate:    formatEndDate(experience.EndDate),
+			Summary:    summary,
+			Highlights: highlights,
+		})
+	}
+
+	for _, education := range resume.Educations {
+		doc.Education = append(doc.Education, schema.JSONResumeEducation{
+			Institution: education.School,
+			StudyType:   education.Degree,
+			Area:        education.Major,
+			StartDate:   formatDate(education.StartDate),
+			EndDate:     formatEndDate(education.EndDate),
+			Score:       education.GPA,
+			Activities:  education.Activities,
+		})
+	}
+
+	for _, project := range resume.Projects {
+		description, highlights := splitHighlights(project.Description)
+		doc.Projects = append(doc.Projects, schema.JSONResumeProject{
+			Name:        project.Title,
+			Description: description,
+			URL:         project.URL,
+			StartDate:   formatDate(project.StartDate),
+			EndDate:     formatEndDate(project.EndDate),
+			Highlights:  highlights,
+			Keywords:    project.Skills,
+		})
+	}
+
+	for _, skill := range resume.Skills {
+		doc.Skills = append(doc.Skills, schema.JSONResumeSkill{Name: skill})
+	}
+
+	return doc
+}
+
+// splitHighlights moves the trailing "- " bullet lines of text into highlights. Text
+// that joinHighlights could not reproduce exactly is kept whole as the summary.
+func splitHighlights(text string) (string, []string) {
+	lines := strings.Split(text, "\n")
+
+	start := len(lines)
+	for start > 0 && strings.HasPrefix(lines[start-1], highlightMarker) {
+		start--
+	}
+	if start == len(lines) {
+		return text, nil
+	}
+
+	summary := strings.Join(lines[:start], "\n")
+	highlights := make([]string, 0, len(lines)-start)
+	for _, line := range lines[start:] {
+		highlights = append(highlights, strings.TrimPrefix(line, highlightMarker))
+	}
+
+	if joinHighlights(summary, highlights) != text {
+		return text, nil
+	}
+	return summary, highlights
+}
+
+// joinHighlights appends highlights to summary as "- " bullet lines.
+func joinHighlights(summary string, highlights []string) string {
+	lines := make([]string, 0, len(highlights)+1)
+	if summary != "" || len(highlights) == 0 {
+		lines = append(lines, summary)
+	}
+	for _, highlight := range highlights {
+		lines = append(lines, highlightMarker+highlight)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(isoDate)
+}
+
+func formatEndDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatDate(*t)
+}
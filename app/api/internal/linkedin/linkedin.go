@@ -0,0 +1,357 @@
+// Package linkedin reads the archive LinkedIn produces under "Download your data".
+package linkedin
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	fileProfile   = "Profile.csv"
+	fileEmails    = "Email Addresses.csv"
+	filePositions = "Positions.csv"
+	fileEducation = "Education.csv"
+	fileProjects  = "Projects.csv"
+	fileSkills    = "Skills.csv"
+)
+
+// untitled is the title of imported resumes whose profile has no name or headline.
+const untitled = "Imported resume"
+
+// maxFileSize bounds how much of each CSV file is read, so a crafted archive cannot
+// expand into unbounded memory.
+const maxFileSize = 5 << 20
+
+// unsupportedFiles are sections of the archive that have no place in a resume. They
+// are reported when they contain data; all other files are ignored silently.
+var unsupportedFiles = []string{
+	"Certifications.csv", "Courses.csv", "Honors.csv", "Languages.csv",
+	"Publications.csv", "Patents.csv", "Volunteering.csv", "Recommendations_Received.csv",
+}
+
+// profileColumns are the Profile.csv columns that are mapped.
+var profileColumns = []string{"First Name", "Last Name", "Headline", "Summary", "Websites"}
+
+var (
+	urlPattern  = regexp.MustCompile(`https?://[^\s,\]]+`)
+	dateLayouts = []string{"Jan 2006", "January 2006", "01/2006", "2006-01", "2006"}
+)
+
+// Detect reports whether data is a zip archive with at least one of the files Decode maps.
+func Detect(data []byte) bool {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+
+	for _, f := range archive.File {
+		switch path.Base(f.Name) {
+		case fileProfile, filePositions, fileEducation, fileProjects, fileSkills:
+			return true
+		}
+	}
+	return false
+}
+
+// Decode maps a LinkedIn data archive onto a new resume without an owner. Rows that
+// cannot be read are reported as errors with their line, and data that has no place in
+// a resume as warnings.
+func Decode(data []byte) (*database.Resume, []schema.ImportWarning, []schema.ImportError, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, nil, common.NewValidationError(common.ErrInvalidImport, common.FieldError{
+			Field:   "file",
+			Reason:  "syntax",
+			Message: "not a zip archive: " + err.Error(),
+		})
+	}
+
+	d := &decoder{files: make(map[string]*zip.File)}
+	for _, f := range archive.File {
+		d.files[path.Base(f.Name)] = f
+	}
+
+	resume := &database.Resume{}
+	d.profile(resume)
+	d.email(resume)
+	d.positions(resume)
+	d.educations(resume)
+	d.projects(resume)
+	d.skills(resume)
+
+	if resume.Title == "" {
+		resume.Title = untitled
+		d.warn(fileProfile, "no name or headline, titled \""+untitled+"\"")
+	}
+
+	for _, name := range unsupportedFiles {
+		if rows := d.read(name); len(rows) > 0 {
+			d.warn(name, fmt.Sprintf("%d rows not supported, ignored", len(rows)))
+		}
+	}
+
+	return resume, d.warnings, d.errors, nil
+}
+
+type decoder struct {
+	files    map[string]*zip.File
+	warnings []schema.ImportWarning
+	errors   []schema.ImportError
+}
+
+// row is a CSV record keyed by column name, with the line it starts on.
+type row struct {
+	line   int
+	values map[string]string
+}
+
+func (r row) get(column string) string {
+	return strings.TrimSpace(r.values[column])
+}
+
+func (d *decoder) warn(field, message string) {
+	d.warnings = append(d.warnings, schema.ImportWarning{Field: field, Message: message})
+}
+
+func (d *decoder) fail(file string, line int, field, message string) {
+	d.errors = append(d.errors, schema.ImportError{File: file, Line: line, Field: field, Message: message})
+}
+
+// read parses the named CSV file. Missing files yield no rows. A file that cannot be
+// parsed is reported at the line of the problem, keeping the rows before it.
+func (d *decoder) read(name string) []row {
+	f, ok := d.files[name]
+	if !ok {
+		return nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		d.fail(name, 0, "", err.Error())
+		return nil
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+	if err != nil {
+		d.fail(name, 0, "", err.Error())
+		return nil
+	}
+	if len(content) > maxFileSize {
+		d.fail(name, 0, "", fmt.Sprintf("file exceeds %d bytes", maxFileSize))
+		return nil
+	}
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var header []string
+	var rows []row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				d.fail(name, parseErr.Line, "", parseErr.Err.Error())
+			} else {
+				d.fail(name, 0, "", err.Error())
+			}
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if header == nil {
+			header = record
+			continue
+		}
+		if len(record) != len(header) {
+			d.fail(name, line, "", fmt.Sprintf("expected %d columns, found %d", len(header), len(record)))
+			continue
+		}
+
+		values := make(map[string]string, len(header))
+		for i, column := range header {
+			values[strings.TrimSpace(column)] = record[i]
+		}
+		rows = append(rows, row{line: line, values: values})
+	}
+
+	return rows
+}
+
+func (d *decoder) profile(resume *database.Resume) {
+	rows := d.read(fileProfile)
+	if len(rows) == 0 {
+		return
+	}
+	profile := rows[0]
+
+	name := strings.TrimSpace(profile.get("First Name") + " " + profile.get("Last Name"))
+	resume.Title = name
+	if headline := profile.get("Headline"); headline != "" {
+		if name == "" {
+			resume.Title = headline
+		} else {
+			resume.Title = name + " — " + headline
+		}
+	}
+	resume.Description = profile.get("Summary")
+
+	websites := urlPattern.FindAllString(profile.get("Websites"), -1)
+	if len(websites) > 0 {
+		resume.URL = websites[0]
+	}
+	if len(websites) > 1 {
+		d.warn(fileProfile+".Websites", "only the first website is imported")
+	}
+
+	columns := make([]string, 0, len(profile.values))
+	for column := range profile.values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		if profile.get(column) != "" && !slices.Contains(profileColumns, column) {
+			d.warn(fileProfile+"."+column, "not supported, ignored")
+		}
+	}
+}
+
+// email uses the primary address, or the first one when none is marked primary.
+func (d *decoder) email(resume *database.Resume) {
+	for _, r := range d.read(fileEmails) {
+		address := r.get("Email Address")
+		if address == "" {
+			continue
+		}
+		if resume.Email == "" || strings.EqualFold(r.get("Primary"), "yes") {
+			resume.Email = address
+		}
+	}
+}
+
+func (d *decoder) positions(resume *database.Resume) {
+	for _, r := range d.read(filePositions) {
+		company, title := r.get("Company Name"), r.get("Title")
+		if company == "" && title == "" {
+			d.fail(filePositions, r.line, "", "company name and title are both empty, row skipped")
+			continue
+		}
+
+		resume.Experiences = append(resume.Experiences, database.Experience{
+			ID:          bson.NewObjectID(),
+			Company:     company,
+			Title:       title,
+			Location:    r.get("Location"),
+			StartDate:   d.startDate(filePositions, r, "Started On"),
+			EndDate:     d.endDate(filePositions, r, "Finished On"),
+			Description: r.get("Description"),
+		})
+	}
+}
+
+func (d *decoder) educations(resume *database.Resume) {
+	for _, r := range d.read(fileEducation) {
+		school := r.get("School Name")
+		if school == "" {
+			d.fail(fileEducation, r.line, "School Name", "school name is empty, row skipped")
+			continue
+		}
+
+		resume.Educations = append(resume.Educations, database.Education{
+			ID:         bson.NewObjectID(),
+			School:     school,
+			Degree:     r.get("Degree Name"),
+			StartDate:  d.startDate(fileEducation, r, "Start Date"),
+			EndDate:    d.endDate(fileEducation, r, "End Date"),
+			Activities: strings.TrimSpace(r.get("Activities") + "\n" + r.get("Notes")),
+		})
+	}
+}
+
+func (d *decoder) projects(resume *database.Resume) {
+	for _, r := range d.read(fileProjects) {
+		title := r.get("Title")
+		if title == "" {
+			d.fail(fileProjects, r.line, "Title", "title is empty, row skipped")
+			continue
+		}
+
+		resume.Projects = append(resume.Projects, database.Project{
+			ID:          bson.NewObjectID(),
+			Title:       title,
+			Description: r.get("Description"),
+			URL:         r.get("Url"),
+			StartDate:   d.startDate(fileProjects, r, "Started On"),
+			EndDate:     d.endDate(fileProjects, r, "Finished On"),
+		})
+	}
+}
+
+func (d *decoder) skills(resume *database.Resume) {
+	for _, r := range d.read(fileSkills) {
+		if name := r.get("Name"); name != "" {
+			resume.Skills = append(resume.Skills, name)
+		}
+	}
+}
+
+// startDate parses a date column. Invalid dates are reported and left empty, keeping
+// the rest of the row.
+func (d *decoder) startDate(file string, r row, column string) time.Time {
+	value := r.get(column)
+	if value == "" {
+		return time.Time{}
+	}
+
+	t, ok := parseDate(value)
+	if !ok {
+		d.fail(file, r.line, column, fmt.Sprintf("invalid date %q, ignored", value))
+	}
+	return t
+}
+
+// endDate parses a date column that is empty for ongoing entries.
+func (d *decoder) endDate(file string, r row, column string) *time.Time {
+	value := r.get(column)
+	if value == "" || strings.EqualFold(value, "present") {
+		return nil
+	}
+
+	t, ok := parseDate(value)
+	if !ok {
+		d.fail(file, r.line, column, fmt.Sprintf("invalid date %q, ignored", value))
+		return nil
+	}
+	return &t
+}
+
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,68 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+)
+
+var mailer Mailer
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(message *Message) error
+}
+
+func init() {
+	mailer = NewMailer(common.GetConfig())
+}
+
+func NewMailer(config *common.Config) Mailer {
+	switch config.Mailer {
+	case "smtp":
+		addr := net.JoinHostPort(config.SMTPHost, config.SMTPPort)
+		return NewSMTPMailer(addr, config.SMTPUsername, config.SMTPPassword, config.MailFrom)
+	case "file":
+		return NewFileMailer(config.MailDir, config.MailFrom)
+	case "memory":
+		return NewMemoryMailer()
+	default:
+		return NewLogMailer(config.MailFrom)
+	}
+}
+
+func GetMailer() Mailer {
+	return mailer
+}
+
+func SetMailer(m Mailer) {
+	mailer = m
+}
+
+func Send(message *Message) error {
+	return mailer.Send(message)
+}
+
+// compose renders the message as an RFC 5322 document with a UTF-8 plain text body.
+func compose(from string, message *Message) []byte {
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "From: %s\r\n", from)
+	_, _ = fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	_, _ = fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	_, _ = fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(message.Body)
+	return buf.Bytes()
+}
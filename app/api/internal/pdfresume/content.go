@@ -0,0 +1,648 @@
+package pdfresume
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// The text of a page is read with our own interpreter rather than Page.Content, which
+// decodes ToUnicode ranges by their last byte only. Fonts embedded as UTF-16, like
+// the ones RenderPDF writes, map every code with a single range and would lose all
+// characters outside Latin-1.
+
+type matrix [3][3]float64
+
+var identity = matrix{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+func (x matrix) mul(y matrix) matrix {
+	var z matrix
+	for i := range 3 {
+		for j := range 3 {
+			for k := range 3 {
+				z[i][j] += x[i][k] * y[k][j]
+			}
+		}
+	}
+	return z
+}
+
+func translate(tx, ty float64) matrix {
+	return matrix{{1, 0, 0}, {0, 1, 0}, {tx, ty, 1}}
+}
+
+func operandMatrix(args []pdf.Value) matrix {
+	m := identity
+	for i := range 6 {
+		m[i/2][i%2] = args[i].Float64()
+	}
+	return m
+}
+
+// textState is the part of the graphics state that places text.
+type textState struct {
+	ctm, tm, tlm         matrix
+	font                 *font
+	size                 float64
+	charSpace, wordSpace float64
+	scale, leading, rise float64
+}
+
+// Limits on the work one file can cause. Forms can draw other forms any number of
+// times and streams can decompress to many times their size, so without them a small
+// crafted file keeps the server busy for hours.
+const (
+	// maxFormDepth bounds how deeply forms drawn by other forms are followed.
+	maxFormDepth = 4
+	// maxFormCalls bounds how many forms are drawn in the whole file.
+	maxFormCalls = 1000
+	// maxGlyphs bounds how many characters are read from the whole file.
+	maxGlyphs = 50_000
+	// maxStreamBytes bounds the decompressed size of the content and ToUnicode streams
+	// read from the whole file, counting a form again every time it is drawn.
+	maxStreamBytes = 32 << 20
+)
+
+// errTooComplex is reported for files that go past one of the limits.
+var errTooComplex = errors.New("the file is too complex to read")
+
+// abort carries an error out of pdf.Interpret, which has no way to stop early. extract
+// recovers it.
+type abort struct {
+	err error
+}
+
+// contentReader interprets the content streams of a file. The limits on its work are
+// shared by all pages, and it stops once ctx is done.
+type contentReader struct {
+	ctx         context.Context
+	forms       int
+	glyphs      int
+	streamBytes int64
+}
+
+func newContentReader(ctx context.Context) *contentReader {
+	return &contentReader{ctx: ctx}
+}
+
+func stop(err error) {
+	panic(abort{err})
+}
+
+func (r *contentReader) checkContext() {
+	if err := r.ctx.Err(); err != nil {
+		stop(err)
+	}
+}
+
+// charge counts the decompressed size of stream, or of each stream in an array,
+// against maxStreamBytes. It reads no further than the limit allows, so a stream
+// that would go past it is never decompressed in full.
+func (r *contentReader) charge(stream pdf.Value) {
+	if stream.Kind() == pdf.Array {
+		for i := 0; i < stream.Len(); i++ {
+			r.charge(stream.Index(i))
+		}
+		return
+	}
+
+	rc := stream.Reader()
+	defer rc.Close()
+
+	remaining := maxStreamBytes - r.streamBytes
+	n, _ := io.Copy(io.Discard, io.LimitReader(rc, remaining+1))
+	if n > remaining {
+		stop(errTooComplex)
+	}
+	r.streamBytes += n
+}
+
+// read returns the decompressed content of stream, counted against maxStreamBytes.
+func (r *contentReader) read(stream pdf.Value) ([]byte, error) {
+	rc := stream.Reader()
+	defer rc.Close()
+
+	remaining := maxStreamBytes - r.streamBytes
+	data, err := io.ReadAll(io.LimitReader(rc, remaining+1))
+	if int64(len(data)) > remaining {
+		stop(errTooComplex)
+	}
+	r.streamBytes += int64(len(data))
+	return data, err
+}
+
+// pageText returns the characters drawn on a page with their position, size and
+// font, in the order they are drawn.
+func (r *contentReader) pageText(page pdf.Page) []pdf.Text {
+	contents := page.V.Key("Contents")
+	if contents.IsNull() {
+		return nil
+	}
+
+	var texts []pdf.Text
+	r.walkText(contents, page.Resources(), identity, 0, &texts)
+	return texts
+}
+
+// walkText interprets a content stream, or a form drawn by one, appending the
+// characters it draws to texts.
+func (r *contentReader) walkText(contents, resources pdf.Value, ctm matrix, depth int, texts *[]pdf.Text) {
+	// The stream is measured before it is interpreted, because pdf.Interpret reads
+	// it without a limit.
+	r.charge(contents)
+
+	fonts := make(map[string]*font)
+	g := textState{ctm: ctm, tm: identity, tlm: identity, scale: 1}
+	var stack []textState
+
+	show := func(raw string) {
+		if g.font == nil {
+			return
+		}
+		// Codes take at most four bytes, so this keeps more characters than the limit
+		// allows without decoding all of a huge string first.
+		raw = raw[:min(len(raw), 4*(maxGlyphs-r.glyphs+1))]
+
+		for _, glyph := range g.font.glyphs(raw) {
+			if r.glyphs++; r.glyphs > maxGlyphs {
+				stop(errTooComplex)
+			}
+
+			trm := matrix{{g.size * g.scale, 0, 0}, {0, g.size, 0}, {0, g.rise, 1}}.mul(g.tm).mul(g.ctm)
+			size := trm[1][1]
+			if size < 0 {
+				size = -size
+			}
+
+			*texts = append(*texts, pdf.Text{
+				Font:     g.font.name,
+				FontSize: size,
+				X:        trm[2][0],
+				Y:        trm[2][1],
+				W:        glyph.width / 1000 * trm[0][0],
+				S:        glyph.text,
+			})
+
+			tx := glyph.width/1000*g.size + g.charSpace
+			if glyph.space {
+				tx += g.wordSpace
+			}
+			g.tm = translate(tx*g.scale, 0).mul(g.tm)
+		}
+	}
+
+	nextLine := func() {
+		g.tlm = translate(0, -g.leading).mul(g.tlm)
+		g.tm = g.tlm
+	}
+
+	pdf.Interpret(contents, func(stk *pdf.Stack, op string) {
+		r.checkContext()
+
+		args := make([]pdf.Value, stk.Len())
+		for i := len(args) - 1; i >= 0; i-- {
+			args[i] = stk.Pop()
+		}
+		operands := func(n int) bool {
+			return len(args) == n
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, g)
+		case "Q":
+			if len(stack) > 0 {
+				g = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case "cm":
+			if operands(6) {
+				g.ctm = operandMatrix(args).mul(g.ctm)
+			}
+		case "BT":
+			g.tm, g.tlm = identity, identity
+		case "Tf":
+			if operands(2) {
+				name := args[0].Name()
+				if _, ok := fonts[name]; !ok {
+					fonts[name] = r.newFont(pdf.Font{V: resources.Key("Font").Key(name)})
+				}
+				g.font, g.size = fonts[name], args[1].Float64()
+			}
+		case "Tc":
+			if operands(1) {
+				g.charSpace = args[0].Float64()
+			}
+		case "Tw":
+			if operands(1) {
+				g.wordSpace = args[0].Float64()
+			}
+		case "Tz":
+			if operands(1) {
+				g.scale = args[0].Float64() / 100
+			}
+		case "TL":
+			if operands(1) {
+				g.leading = args[0].Float64()
+			}
+		case "Ts":
+			if operands(1) {
+				g.rise = args[0].Float64()
+			}
+		case "Td", "TD":
+			if operands(2) {
+				if op == "TD" {
+					g.leading = -args[1].Float64()
+				}
+				g.tlm = translate(args[0].Float64(), args[1].Float64()).mul(g.tlm)
+				g.tm = g.tlm
+			}
+		case "Tm":
+			if operands(6) {
+				g.tm = operandMatrix(args)
+				g.tlm = g.tm
+			}
+		case "T*":
+			nextLine()
+		case "Tj":
+			if operands(1) {
+				show(args[0].RawString())
+			}
+		case "'":
+			if operands(1) {
+				nextLine()
+				show(args[0].RawString())
+			}
+		case "\"":
+			if operands(3) {
+				g.wordSpace, g.charSpace = args[0].Float64(), args[1].Float64()
+				nextLine()
+				show(args[2].RawString())
+			}
+		case "Do":
+			if !operands(1) || depth >= maxFormDepth {
+				return
+			}
+			form := resources.Key("XObject").Key(args[0].Name())
+			if form.Key("Subtype").Name() != "Form" {
+				return
+			}
+			if r.forms++; r.forms > maxFormCalls {
+				stop(errTooComplex)
+			}
+
+			m := identity
+			if values := form.Key("Matrix"); values.Len() == 6 {
+				for i := range 6 {
+					m[i/2][i%2] = values.Index(i).Float64()
+				}
+			}
+			formResources := form.Key("Resources")
+			if formResources.IsNull() {
+				formResources = resources
+			}
+			r.walkText(form, formResources, m.mul(g.ctm), depth+1, texts)
+		case "TJ":
+			if operands(1) {
+				for i := 0; i < args[0].Len(); i++ {
+					v := args[0].Index(i)
+					if v.Kind() == pdf.String {
+						show(v.RawString())
+					} else {
+						g.tm = translate(-v.Float64()/1000*g.size*g.scale, 0).mul(g.tm)
+					}
+				}
+			}
+		}
+	})
+}
+
+// glyph is a decoded character code with its advance in thousandths of an em.
+type glyph struct {
+	text  string
+	width float64
+	space bool
+}
+
+// font decodes the character codes of a font. Composite fonts use multi-byte codes
+// and a width table of their own; simple fonts use one byte per code.
+type font struct {
+	name      string
+	composite bool
+	toUnicode *cmap
+	encoding  pdf.TextEncoding
+	simple    pdf.Font
+	widths    map[int]float64
+	dw        float64
+}
+
+func (r *contentReader) newFont(v pdf.Font) *font {
+	name := v.BaseFont()
+	if i := strings.Index(name, "+"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	f := &font{name: name, simple: v, composite: v.V.Key("Subtype").Name() == "Type0"}
+	if toUnicode := v.V.Key("ToUnicode"); toUnicode.Kind() == pdf.Stream {
+		if data, err := r.read(toUnicode); err == nil {
+			f.toUnicode = readCmap(data)
+		}
+	}
+	if !f.composite {
+		f.encoding = v.Encoder()
+	}
+
+	if f.composite {
+		descendant := v.V.Key("DescendantFonts").Index(0)
+		f.dw = 1000
+		if dw := descendant.Key("DW"); dw.Kind() == pdf.Integer || dw.Kind() == pdf.Real {
+			f.dw = dw.Float64()
+		}
+		f.widths = compositeWidths(descendant.Key("W"))
+	}
+	return f
+}
+
+func (f *font) glyphs(raw string) []glyph {
+	var glyphs []glyph
+	for len(raw) > 0 {
+		var code string
+		var text string
+		switch {
+		case f.composite && f.toUnicode != nil:
+			code, text = f.toUnicode.decode(raw, true)
+		case f.composite:
+			// Without a ToUnicode map the codes of a composite font are glyph
+			// numbers, which cannot be mapped back to text.
+			code = raw[:min(2, len(raw))]
+		default:
+			// The ToUnicode maps of simple fonts often cover only ligatures and
+			// symbols; other codes are read with the font's encoding.
+			code = raw[:1]
+			if f.toUnicode != nil {
+				_, text = f.toUnicode.decode(code, false)
+			}
+			if text == "" {
+				text = f.encoding.Decode(code)
+			}
+		}
+		raw = raw[len(code):]
+
+		value := codeValue(code)
+		g := glyph{text: text, space: len(code) == 1 && value == ' '}
+		if f.composite {
+			g.width = f.dw
+			if w, ok := f.widths[value]; ok {
+				g.width = w
+			}
+		} else {
+			g.width = f.simple.Width(value)
+		}
+		// The standard fonts come without widths. Assuming half an em keeps the
+		// characters of a run together and gaps between runs measurable.
+		if g.width == 0 {
+			g.width = 500
+		}
+		glyphs = append(glyphs, g)
+	}
+	return glyphs
+}
+
+// compositeWidths reads a W array, which lists widths either as "first [w1 w2 ...]"
+// or as "first last w".
+func compositeWidths(w pdf.Value) map[int]float64 {
+	widths := make(map[int]float64)
+	for i := 0; i+1 < w.Len(); {
+		first := int(w.Index(i).Int64())
+		if next := w.Index(i + 1); next.Kind() == pdf.Array {
+			for j := 0; j < next.Len(); j++ {
+				widths[first+j] = next.Index(j).Float64()
+			}
+			i += 2
+			continue
+		}
+
+		if i+2 >= w.Len() {
+			break
+		}
+		last, width := int(w.Index(i+1).Int64()), w.Index(i+2).Float64()
+		for code := first; code <= last && code-first < 0x10000; code++ {
+			widths[code] = width
+		}
+		i += 3
+	}
+	return widths
+}
+
+// cmap is a ToUnicode map.
+type cmap struct {
+	spaces [][2]string
+	chars  map[string]string
+	ranges []cmapRange
+}
+
+// cmapRange maps a range of codes either to consecutive text, starting at dst, or
+// to the texts listed in dsts.
+type cmapRange struct {
+	low, high string
+	dst       string
+	dsts      []string
+}
+
+// readCmap reads the mappings of a ToUnicode stream. Only the codespace and bf
+// sections are read, so the PostScript around them is skipped rather than run.
+func readCmap(data []byte) *cmap {
+	m := &cmap{chars: make(map[string]string)}
+	var operands [][]string
+	for len(data) > 0 {
+		c := data[0]
+		switch {
+		case c == '%':
+			if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+				data = data[i:]
+			} else {
+				data = nil
+			}
+		case c == '<' && len(data) > 1 && data[1] == '<', c == '>' && len(data) > 1 && data[1] == '>':
+			data = data[2:]
+		case c == '<':
+			var s string
+			s, data = readHex(data)
+			operands = append(operands, []string{s})
+		case c == '[':
+			var array []string
+			data = data[1:]
+			for len(data) > 0 && data[0] != ']' {
+				if data[0] == '<' {
+					var s string
+					s, data = readHex(data)
+					array = append(array, s)
+				} else {
+					data = data[1:]
+				}
+			}
+			if len(data) > 0 {
+				data = data[1:]
+			}
+			operands = append(operands, array)
+		case c == '(':
+			data = skipLiteral(data)
+		case isDelimiter(c):
+			data = data[1:]
+		default:
+			i := 0
+			for i < len(data) && !isDelimiter(data[i]) {
+				i++
+			}
+			word := string(data[:i])
+			data = data[i:]
+
+			switch word {
+			case "begincodespacerange", "beginbfchar", "beginbfrange":
+				operands = nil
+			case "endcodespacerange":
+				for i := 0; i+1 < len(operands); i += 2 {
+					m.spaces = append(m.spaces, [2]string{first(operands[i]), first(operands[i+1])})
+				}
+			case "endbfchar":
+				for i := 0; i+1 < len(operands); i += 2 {
+					m.chars[first(operands[i])] = utf16Text(first(operands[i+1]))
+				}
+			case "endbfrange":
+				for i := 0; i+2 < len(operands); i += 3 {
+					r := cmapRange{low: first(operands[i]), high: first(operands[i+1])}
+					if dst := operands[i+2]; len(dst) == 1 {
+						r.dst = dst[0]
+					} else {
+						r.dsts = dst
+					}
+					m.ranges = append(m.ranges, r)
+				}
+			}
+		}
+	}
+	return m
+}
+
+func readHex(data []byte) (string, []byte) {
+	end := bytes.IndexByte(data, '>')
+	if end < 0 {
+		end = len(data)
+	}
+
+	digits := make([]byte, 0, end)
+	for _, c := range data[1:end] {
+		if strings.IndexByte("0123456789abcdefABCDEF", c) >= 0 {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	decoded, _ := hex.DecodeString(string(digits))
+
+	if end < len(data) {
+		end++
+	}
+	return string(decoded), data[end:]
+}
+
+// skipLiteral skips a string in parentheses, which may nest and escape them.
+func skipLiteral(data []byte) []byte {
+	depth := 0
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return data[i+1:]
+			}
+		}
+	}
+	return nil
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte(" \t\r\n\f\x00()<>[]{}/%", c) >= 0
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// decode reads the first code of raw and its text. Codes outside the codespace take
+// one byte, or two in composite fonts.
+func (m *cmap) decode(raw string, composite bool) (string, string) {
+	code := ""
+	for _, space := range m.spaces {
+		n := len(space[0])
+		if n <= len(raw) && space[0] <= raw[:n] && raw[:n] <= space[1] && n > len(code) {
+			code = raw[:n]
+		}
+	}
+	if code == "" {
+		code = raw[:1]
+		if composite && len(raw) >= 2 {
+			code = raw[:2]
+		}
+	}
+
+	if text, ok := m.chars[code]; ok {
+		return code, text
+	}
+	for _, r := range m.ranges {
+		if len(r.low) != len(code) || code < r.low || code > r.high {
+			continue
+		}
+
+		offset := codeValue(code) - codeValue(r.low)
+		if r.dsts != nil {
+			if offset < len(r.dsts) {
+				return code, utf16Text(r.dsts[offset])
+			}
+			return code, ""
+		}
+
+		units := utf16Units(r.dst)
+		if len(units) == 0 {
+			return code, ""
+		}
+		units[len(units)-1] += uint16(offset)
+		return code, string(utf16.Decode(units))
+	}
+	return code, ""
+}
+
+// codeValue reads a character code as a big-endian number.
+func codeValue(code string) int {
+	value := 0
+	for i := 0; i < len(code); i++ {
+		value = value<<8 | int(code[i])
+	}
+	return value
+}
+
+func utf16Units(s string) []uint16 {
+	units := make([]uint16, 0, len(s)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return units
+}
+
+func utf16Text(s string) string {
+	return string(utf16.Decode(utf16Units(s)))
+}
@@ -0,0 +1,140 @@
+package pdfresume
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+)
+
+// buildPDF writes a one-page PDF. objects are the bodies of objects 4 and on; the
+// catalog, page tree and page are objects 1 to 3, and the page uses object 4 as its
+// content stream and resources as its resource dictionary.
+func buildPDF(resources string, objects ...string) []byte {
+	all := append([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources " + resources + " >>",
+	}, objects...)
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(all))
+	for i, object := range all {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(all)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(all)+1, xref)
+	return b.Bytes()
+}
+
+func stream(dict, content string) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(content), content)
+}
+
+func flateStream(dict string, content []byte) string {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write(content)
+	w.Close()
+	return stream("/Filter /FlateDecode "+dict, b.String())
+}
+
+const helvetica = "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"
+
+func decodeReason(t *testing.T, ctx context.Context, data []byte) string {
+	t.Helper()
+
+	_, _, _, err := Decode(ctx, data)
+	var validationErr *common.ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, common.ErrInvalidImport) {
+		t.Fatalf("Decode error = %v, want an invalid import", err)
+	}
+	return validationErr.Fields[0].Reason
+}
+
+func TestDecodeReadsHandBuiltPDF(t *testing.T) {
+	data := buildPDF("<< /Font << /F1 5 0 R >> >>",
+		stream("", "BT /F1 24 Tf 72 720 Td (Jane Doe) Tj ET"),
+		helvetica,
+	)
+
+	resume, _, _, err := Decode(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resume.Title != "Jane Doe" {
+		t.Errorf("title = %q, want Jane Doe", resume.Title)
+	}
+}
+
+func TestDecodeLimitsFormCalls(t *testing.T) {
+	// The form draws itself ten times at every level, which is 10^4 forms in all.
+	data := buildPDF("<< /XObject << /X 5 0 R >> >>",
+		stream("", "/X Do"),
+		stream("/Type /XObject /Subtype /Form /BBox [0 0 10 10] /Resources << /XObject << /X 5 0 R >> >>", strings.Repeat("/X Do\n", 10)),
+	)
+
+	if reason := decodeReason(t, context.Background(), data); reason != "too_complex" {
+		t.Errorf("reason = %q, want too_complex", reason)
+	}
+}
+
+func TestDecodeLimitsGlyphs(t *testing.T) {
+	data := buildPDF("<< /Font << /F1 5 0 R >> >>",
+		stream("", "BT /F1 1 Tf ("+strings.Repeat("x", maxGlyphs+1)+") Tj ET"),
+		helvetica,
+	)
+
+	if reason := decodeReason(t, context.Background(), data); reason != "too_complex" {
+		t.Errorf("reason = %q, want too_complex", reason)
+	}
+}
+
+func TestDecodeLimitsDecompressedStreams(t *testing.T) {
+	bomb := make([]byte, maxStreamBytes+1)
+
+	t.Run("content", func(t *testing.T) {
+		data := buildPDF("<< >>", flateStream("", bomb))
+		if reason := decodeReason(t, context.Background(), data); reason != "too_complex" {
+			t.Errorf("reason = %q, want too_complex", reason)
+		}
+	})
+
+	t.Run("ToUnicode", func(t *testing.T) {
+		data := buildPDF("<< /Font << /F1 5 0 R >> >>",
+			stream("", "BT /F1 12 Tf (x) Tj ET"),
+			"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /ToUnicode 6 0 R >>",
+			flateStream("", bomb),
+		)
+		if reason := decodeReason(t, context.Background(), data); reason != "too_complex" {
+			t.Errorf("reason = %q, want too_complex", reason)
+		}
+	})
+}
+
+func TestDecodeStopsAtDeadline(t *testing.T) {
+	data := buildPDF("<< /Font << /F1 5 0 R >> >>",
+		stream("", "BT /F1 24 Tf 72 720 Td (Jane Doe) Tj ET"),
+		helvetica,
+	)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	if reason := decodeReason(t, ctx, data); reason != "timeout" {
+		t.Errorf("reason = %q, want timeout", reason)
+	}
+}
@@ -0,0 +1,693 @@
+package pdfresume
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// untitled is the title of drafts without a recognizable name.
+const untitled = "Imported resume"
+
+const (
+	sectionSummary    = "summary"
+	sectionExperience = "experiences"
+	sectionEducation  = "educations"
+	sectionProjects   = "projects"
+	sectionSkills     = "skills"
+	sectionOther      = "other"
+)
+
+// headings maps normalized section headings to the section they start.
+var headings = map[string]string{
+	"summary":                    sectionSummary,
+	"professional summary":       sectionSummary,
+	"profile":                    sectionSummary,
+	"about":                      sectionSummary,
+	"about me":                   sectionSummary,
+	"objective":                  sectionSummary,
+	"experience":                 sectionExperience,
+	"work experience":            sectionExperience,
+	"professional experience":    sectionExperience,
+	"employment":                 sectionExperience,
+	"employment history":         sectionExperience,
+	"work history":               sectionExperience,
+	"career":                     sectionExperience,
+	"education":                  sectionEducation,
+	"academic background":        sectionEducation,
+	"education and training":     sectionEducation,
+	"projects":                   sectionProjects,
+	"personal projects":          sectionProjects,
+	"selected projects":          sectionProjects,
+	"side projects":              sectionProjects,
+	"open source":                sectionProjects,
+	"skills":                     sectionSkills,
+	"technical skills":           sectionSkills,
+	"skills and tools":           sectionSkills,
+	"core competencies":          sectionSkills,
+	"technologies":               sectionSkills,
+	"tech stack":                 sectionSkills,
+	"certifications":             sectionOther,
+	"certificates":               sectionOther,
+	"awards":                     sectionOther,
+	"honors":                     sectionOther,
+	"honors and awards":          sectionOther,
+	"languages":                  sectionOther,
+	"publications":               sectionOther,
+	"interests":                  sectionOther,
+	"hobbies":                    sectionOther,
+	"references":                 sectionOther,
+	"volunteering":               sectionOther,
+	"volunteer experience":       sectionOther,
+	"achievements":               sectionOther,
+	"courses":                    sectionOther,
+	"extracurricular":            sectionOther,
+	"extracurricular activities": sectionOther,
+}
+
+const (
+	month = `(?:jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)\.?`
+	date  = `(?:` + month + `\s+\d{4}|\d{1,2}/\d{4}|\d{4}[./-]\d{1,2}|\d{4})`
+)
+
+var (
+	rangePattern = regexp.MustCompile(`(?i)\b(` + date + `)\s*(?:-|–|—|~|to)\s*(` + date + `|present|current|now|ongoing)\b`)
+	datePattern  = regexp.MustCompile(`(?i)\b` + date + `\b`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	urlPattern   = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s,;|·]+|\b(?:linkedin\.com|github\.com)/[^\s,;|·]+`)
+	gpaPattern   = regexp.MustCompile(`(?i)\bGPA:?\s*(\d+(?:\.\d+)?(?:\s*/\s*\d+(?:\.\d+)?)?)`)
+	separator    = regexp.MustCompile(`\s*[·|•]\s*|\s+[–—@-]\s+|\s+at\s+`)
+
+	locationPattern = regexp.MustCompile(`^(?:\p{Lu}[\p{L}.' ]+, \p{Lu}[\p{L}.' ]+|[Rr]emote)$`)
+	rolePattern     = regexp.MustCompile(`(?i)\b(?:engineer|developer|programmer|manager|intern|designer|analyst|scientist|consultant|lead|director|architect|specialist|assistant|researcher|officer|administrator|founder|head|cto|ceo)\b`)
+	orgPattern      = regexp.MustCompile(`(?i)\b(?:inc|corp|corporation|llc|ltd|gmbh|co|company|technologies|labs?|group|bank|studio|solutions|systems|software|agency)\b\.?$`)
+	schoolPattern   = regexp.MustCompile(`(?i)\b(?:university|college|school|institute|academy|polytechnic)\b`)
+	degreePattern   = regexp.MustCompile(`(?i)(?:\b(?:bachelor|master|associate|diploma|doctor|mba|bsc|msc|phd|ba|bs|ma|ms|beng|meng)\b|\b[BM]\.[AS]\.|\bPh\.D\.)`)
+	studyPattern    = regexp.MustCompile(schoolPattern.String() + "|" + degreePattern.String())
+
+	bulletMarkers = []string{"•", "●", "▪", "◦", "‣", "-", "*", "–", "·"}
+)
+
+var dateLayouts = []string{"Jan 2006", "January 2006", "1/2006", "2006.1", "2006-1", "2006"}
+
+// Detect reports whether data is a PDF file.
+func Detect(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("%PDF-"))
+}
+
+// Decode drafts a resume without an owner from the text of a PDF file. Each field that
+// was filled is listed in the confidence map by its JSON path, such as
+// experiences[0].company, with a value between 0 and 1. Sections that have no place
+// in a resume, and text that could not be assigned to a field, are reported as
+// warnings.
+//
+// The text is read in a single column from the top of each page, so two-column
+// layouts are drafted poorly. Scanned documents have no text and are rejected, and so
+// are files that take more work to read than a resume needs or are not read before
+// ctx is done.
+func Decode(ctx context.Context, data []byte) (*database.Resume, []schema.ImportWarning, map[string]float64, error) {
+	lines, pages, err := extract(ctx, data)
+	if err != nil {
+		return nil, nil, nil, extractError(err)
+	}
+	if len(lines) == 0 {
+		return nil, nil, nil, common.NewValidationError(common.ErrInvalidImport, common.FieldError{
+			Field:   "file",
+			Reason:  "empty",
+			Message: "the file has no text; scanned documents are not supported",
+		})
+	}
+
+	d := &decoder{body: bodySize(lines), confidence: make(map[string]float64)}
+	if pages > maxPages {
+		d.warn("file", fmt.Sprintf("only the first %d of %d pages are read", maxPages, pages))
+	}
+
+	resume := &database.Resume{}
+	header, sections := d.split(lines)
+	d.header(resume, header)
+
+	for _, s := range sections {
+		switch s.name {
+		case sectionSummary:
+			resume.Description = strings.Join(descriptionLines(s.lines), "\n")
+			d.set("description", 0.85*s.confidence)
+		case sectionExperience:
+			d.experiences(resume, s)
+		case sectionEducation:
+			d.educations(resume, s)
+		case sectionProjects:
+			d.projects(resume, s)
+		case sectionSkills:
+			d.skills(resume, s)
+		default:
+			d.warn(s.heading, "section not supported, ignored")
+		}
+	}
+
+	if resume.Title == "" {
+		resume.Title = untitled
+	}
+	return resume, d.warnings, d.confidence, nil
+}
+
+// extractError reports why the text of a file could not be read.
+func extractError(err error) error {
+	fieldErr := common.FieldError{Field: "file", Reason: "syntax", Message: "not a readable PDF file: " + err.Error()}
+	switch {
+	case errors.Is(err, errTooComplex):
+		fieldErr.Reason, fieldErr.Message = "too_complex", err.Error()
+	case errors.Is(err, context.DeadlineExceeded):
+		fieldErr.Reason, fieldErr.Message = "timeout", "the file took too long to read"
+	}
+	return common.NewValidationError(common.ErrInvalidImport, fieldErr)
+}
+
+type decoder struct {
+	body       float64
+	warnings   []schema.ImportWarning
+	confidence map[string]float64
+}
+
+type section struct {
+	name       string
+	heading    string
+	confidence float64
+	lines      []line
+}
+
+// entry is the text of one experience, education or project.
+type entry struct {
+	parts          []string
+	dateParts      []string
+	start          time.Time
+	end            *time.Time
+	dateConfidence float64
+	lines          []line
+	confidence     float64
+}
+
+func (d *decoder) warn(field, message string) {
+	d.warnings = append(d.warnings, schema.ImportWarning{Field: field, Message: message})
+}
+
+func (d *decoder) set(field string, confidence float64) {
+	d.confidence[field] = math.Round(confidence*100) / 100
+}
+
+func (d *decoder) emphasized(l line) bool {
+	return l.bold || l.size > d.body*1.05
+}
+
+// split separates the lines before the first section heading from the sections.
+func (d *decoder) split(lines []line) ([]line, []*section) {
+	var header []line
+	var sections []*section
+	for _, l := range lines {
+		if name, confidence, ok := d.heading(l); ok {
+			sections = append(sections, &section{name: name, heading: l.text(), confidence: confidence})
+			continue
+		}
+
+		if len(sections) == 0 {
+			header = append(header, l)
+		} else {
+			s := sections[len(sections)-1]
+			s.lines = append(s.lines, l)
+		}
+	}
+	return header, sections
+}
+
+// heading recognizes section headings by their wording, and is more confident when
+// they stand out from the body text.
+func (d *decoder) heading(l line) (string, float64, bool) {
+	text := l.text()
+	if utf8.RuneCountInString(text) > 40 {
+		return "", 0, false
+	}
+
+	key := strings.ToLower(strings.TrimRight(text, ": "))
+	key = strings.Join(strings.Fields(strings.ReplaceAll(key, "&", " and ")), " ")
+	name, ok := headings[key]
+	if !ok {
+		return "", 0, false
+	}
+
+	if d.emphasized(l) || text == strings.ToUpper(text) {
+		return name, 0.95, true
+	}
+	return name, 0.7, true
+}
+
+// header fills the name and contact details from the lines above the first section.
+// The name is the line set in the largest type.
+func (d *decoder) header(resume *database.Resume, lines []line) {
+	title := -1
+	for i, l := range lines {
+		text := l.text()
+		if emailPattern.MatchString(text) || urlPattern.MatchString(text) {
+			continue
+		}
+		if title < 0 || l.size > lines[title].size {
+			title = i
+		}
+	}
+	if title >= 0 {
+		resume.Title = lines[title].text()
+		if lines[title].size > d.body*1.2 {
+			d.set("title", 0.9)
+		} else {
+			d.set("title", 0.6)
+		}
+	}
+
+	var summary []line
+	for i, l := range lines {
+		text := l.text()
+		if resume.Email == "" {
+			if email := emailPattern.FindString(text); email != "" {
+				resume.Email = email
+				d.set("email", 0.95)
+			}
+		}
+		if resume.URL == "" {
+			for _, url := range urlPattern.FindAllString(emailPattern.ReplaceAllString(text, ""), -1) {
+				url = strings.TrimRight(url, ".)")
+				if strings.HasPrefix(strings.ToLower(url), "http") {
+					resume.URL = url
+					d.set("url", 0.8)
+				} else {
+					resume.URL = "https://" + url
+					d.set("url", 0.6)
+				}
+				break
+			}
+		}
+
+		// Long lines under the name are usually a summary without a heading.
+		if i != title && utf8.RuneCountInString(text) >= 60 && !emailPattern.MatchString(text) {
+			summary = append(summary, l)
+		}
+	}
+	if len(summary) > 0 {
+		resume.Description = strings.Join(descriptionLines(summary), "\n")
+		d.set("description", 0.5)
+	}
+}
+
+// entries splits a section into entries. An entry starts at a line with a date range,
+// together with the emphasized lines right above it, such as a job title. Sections
+// without dates are split at emphasized lines.
+func (d *decoder) entries(s *section, keyword *regexp.Regexp) []entry {
+	// Dates in bullets and sentences belong to a description.
+	var dated []int
+	for _, pattern := range []*regexp.Regexp{rangePattern, datePattern} {
+		for i, l := range s.lines {
+			text := l.text()
+			if !isBullet(text) && utf8.RuneCountInString(text) <= 100 && pattern.MatchString(text) {
+				dated = append(dated, i)
+			}
+		}
+		if len(dated) > 0 {
+			break
+		}
+	}
+
+	type bounds struct{ first, date, last int }
+	var found []bounds
+	if len(dated) > 0 {
+		previous := -1
+		for _, i := range dated {
+			b := bounds{first: i, date: i, last: i}
+			for k := i - 1; k > previous && k >= i-2 && d.isHeaderLine(s.lines[k], keyword); k-- {
+				b.first = k
+			}
+			if b.first == i && i+1 < len(s.lines) && !slices.Contains(dated, i+1) && d.isHeaderLine(s.lines[i+1], keyword) {
+				b.last = i + 1
+			}
+			found = append(found, b)
+			previous = b.last
+		}
+	} else {
+		for i, l := range s.lines {
+			if d.isHeaderLine(l, keyword) && d.emphasized(l) {
+				found = append(found, bounds{first: i, date: -1, last: i})
+			}
+		}
+	}
+
+	if len(found) == 0 {
+		d.warn(s.heading, fmt.Sprintf("%d lines could not be split into entries, ignored", len(s.lines)))
+		return nil
+	}
+	if found[0].first > 0 {
+		d.warn(s.heading, fmt.Sprintf("%d lines before the first entry, ignored", found[0].first))
+	}
+
+	entries := make([]entry, 0, len(found))
+	for n, b := range found {
+		e := entry{confidence: s.confidence}
+		if len(dated) == 0 {
+			e.confidence *= 0.6
+		}
+
+		for i := b.first; i <= b.last; i++ {
+			for _, segment := range s.lines[i].segments {
+				if i != b.date {
+					e.parts = append(e.parts, splitParts(segment)...)
+					continue
+				}
+
+				if m := rangePattern.FindStringSubmatchIndex(segment); m != nil {
+					start, _ := parseDate(segment[m[2]:m[3]])
+					e.start = start
+					if end, ok := parseDate(segment[m[4]:m[5]]); ok {
+						e.end = &end
+					}
+					e.dateConfidence = 0.9
+					segment = segment[:m[0]] + " · " + segment[m[1]:]
+				} else if m := datePattern.FindStringIndex(segment); m != nil {
+					e.start, _ = parseDate(segment[m[0]:m[1]])
+					e.dateConfidence = 0.6
+					segment = segment[:m[0]] + " · " + segment[m[1]:]
+				}
+				e.dateParts = append(e.dateParts, splitParts(segment)...)
+			}
+		}
+
+		next := len(s.lines)
+		if n+1 < len(found) {
+			next = found[n+1].first
+		}
+		e.lines = s.lines[b.last+1 : next]
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// isHeaderLine reports whether l can be part of an entry's heading rather than its
+// description: short, not a bullet or sentence, and set apart or naming what the
+// section is about.
+func (d *decoder) isHeaderLine(l line, keyword *regexp.Regexp) bool {
+	text := l.text()
+	if isBullet(text) || utf8.RuneCountInString(text) > 80 || strings.HasSuffix(text, ".") {
+		return false
+	}
+	return d.emphasized(l) || keyword != nil && keyword.MatchString(text)
+}
+
+func (d *decoder) experiences(resume *database.Resume, s *section) {
+	for _, e := range d.entries(s, rolePattern) {
+		experience := database.Experience{ID: bson.NewObjectID(), StartDate: e.start, EndDate: e.end}
+		prefix := fmt.Sprintf("experiences[%d].", len(resume.Experiences))
+
+		var parts []string
+		for _, part := range append(e.parts, e.dateParts...) {
+			if experience.Location == "" && isLocation(part) {
+				experience.Location = part
+				d.set(prefix+"location", 0.6*e.confidence)
+			} else {
+				parts = append(parts, part)
+			}
+		}
+
+		values, confidences := assign(parts, rolePattern, orgPattern)
+		experience.Title, experience.Company = values[0], values[1]
+		if experience.Title == "" && experience.Company == "" {
+			d.warn(s.heading, "an entry without a title or company, ignored")
+			continue
+		}
+		d.setText(prefix+"title", experience.Title, confidences[0]*e.confidence)
+		d.setText(prefix+"company", experience.Company, confidences[1]*e.confidence)
+		d.setDates(prefix, e)
+
+		experience.Description = strings.Join(descriptionLines(e.lines), "\n")
+		d.setText(prefix+"description", experience.Description, 0.6*e.confidence)
+
+		resume.Experiences = append(resume.Experiences, experience)
+	}
+}
+
+func (d *decoder) educations(resume *database.Resume, s *section) {
+	for _, e := range d.entries(s, studyPattern) {
+		education := database.Education{ID: bson.NewObjectID(), StartDate: e.start, EndDate: e.end}
+		prefix := fmt.Sprintf("educations[%d].", len(resume.Educations))
+
+		// A single date is usually when the degree was awarded.
+		if e.end == nil && e.dateConfidence < 0.9 && !e.start.IsZero() {
+			end := e.start
+			education.StartDate, education.EndDate = time.Time{}, &end
+		}
+
+		// Locations next to the dates are left out; educations have no place for them.
+		var parts []string
+		for i, part := range append(e.parts, e.dateParts...) {
+			if m := gpaPattern.FindStringSubmatchIndex(part); m != nil && education.GPA == "" {
+				education.GPA = strings.Join(strings.Fields(part[m[2]:m[3]]), "")
+				d.set(prefix+"gpa", 0.9*e.confidence)
+				part = part[:m[0]] + part[m[1]:]
+			}
+			if i >= len(e.parts) && isLocation(part) {
+				continue
+			}
+			parts = append(parts, splitList(part)...)
+		}
+
+		values, confidences := assign(parts, schoolPattern, degreePattern, nil)
+		education.School, education.Degree, education.Major = values[0], values[1], values[2]
+		if degree, major, ok := strings.Cut(education.Degree, " in "); ok && education.Major == "" {
+			education.Degree, education.Major = degree, major
+			confidences[2] = confidences[1]
+		}
+		if education.School == "" {
+			d.warn(s.heading, "an entry without a school, ignored")
+			continue
+		}
+		d.setText(prefix+"school", education.School, confidences[0]*e.confidence)
+		d.setText(prefix+"degree", education.Degree, confidences[1]*e.confidence)
+		d.setText(prefix+"major", education.Major, confidences[2]*e.confidence)
+		if education.EndDate != nil && education.StartDate.IsZero() {
+			d.set(prefix+"endDate", e.dateConfidence*e.confidence)
+		} else {
+			d.setDates(prefix, e)
+		}
+
+		education.Activities = strings.Join(descriptionLines(e.lines), "\n")
+		d.setText(prefix+"activities", education.Activities, 0.6*e.confidence)
+
+		resume.Educations = append(resume.Educations, education)
+	}
+}
+
+func (d *decoder) projects(resume *database.Resume, s *section) {
+	for _, e := range d.entries(s, nil) {
+		project := database.Project{ID: bson.NewObjectID(), StartDate: e.start, EndDate: e.end}
+		prefix := fmt.Sprintf("projects[%d].", len(resume.Projects))
+
+		var parts []string
+		for _, part := range append(e.parts, e.dateParts...) {
+			if url := urlPattern.FindString(part); url != "" && project.URL == "" {
+				if !strings.HasPrefix(strings.ToLower(url), "http") {
+					url = "https://" + url
+				}
+				project.URL = url
+				d.set(prefix+"url", 0.9*e.confidence)
+				continue
+			}
+			parts = append(parts, part)
+		}
+
+		if len(parts) == 0 {
+			d.warn(s.heading, "an entry without a title, ignored")
+			continue
+		}
+		project.Title = parts[0]
+		d.set(prefix+"title", 0.7*e.confidence)
+		d.setDates(prefix, e)
+
+		project.Description = strings.Join(descriptionLines(e.lines), "\n")
+		d.setText(prefix+"description", project.Description, 0.6*e.confidence)
+
+		resume.Projects = append(resume.Projects, project)
+	}
+}
+
+// skills splits the section into items at commas and similar separators, dropping
+// labels such as "Languages:".
+func (d *decoder) skills(resume *database.Resume, s *section) {
+	seen := make(map[string]bool)
+	for _, l := range s.lines {
+		text := trimBullet(l.text())
+		if label, rest, ok := strings.Cut(text, ":"); ok && utf8.RuneCountInString(label) <= 30 {
+			text = rest
+		}
+
+		for _, item := range strings.FieldsFunc(text, func(r rune) bool {
+			return strings.ContainsRune(",;|•·", r)
+		}) {
+			item = strings.TrimSpace(item)
+			if item == "" || utf8.RuneCountInString(item) > 50 || seen[strings.ToLower(item)] {
+				continue
+			}
+			seen[strings.ToLower(item)] = true
+			resume.Skills = append(resume.Skills, item)
+		}
+	}
+
+	if len(resume.Skills) > 0 {
+		d.set("skills", 0.7*s.confidence)
+	}
+}
+
+func (d *decoder) setText(field, value string, confidence float64) {
+	if value != "" {
+		d.set(field, confidence)
+	}
+}
+
+func (d *decoder) setDates(prefix string, e entry) {
+	if !e.start.IsZero() {
+		d.set(prefix+"startDate", e.dateConfidence*e.confidence)
+	}
+	if e.end != nil {
+		d.set(prefix+"endDate", e.dateConfidence*e.confidence)
+	}
+}
+
+// assign fills one value per pattern from parts. Parts matching a pattern are taken
+// first; the remaining values are filled in order with the parts left over, with less
+// confidence. A nil pattern matches nothing.
+func assign(parts []string, patterns ...*regexp.Regexp) ([]string, []float64) {
+	values := make([]string, len(patterns))
+	confidences := make([]float64, len(patterns))
+	used := make([]bool, len(parts))
+
+	for i, pattern := range patterns {
+		if pattern == nil {
+			continue
+		}
+		for j, part := range parts {
+			if !used[j] && pattern.MatchString(part) {
+				values[i], confidences[i], used[j] = part, 0.8, true
+				break
+			}
+		}
+	}
+
+	j := 0
+	for i := range values {
+		if values[i] != "" {
+			continue
+		}
+		for j < len(parts) && used[j] {
+			j++
+		}
+		if j < len(parts) {
+			values[i], confidences[i], used[j] = parts[j], 0.5, true
+		}
+	}
+	return values, confidences
+}
+
+// isLocation reports parts like "Seoul, Korea" or "Remote" that do not name an
+// organization.
+func isLocation(part string) bool {
+	return locationPattern.MatchString(part) && !orgPattern.MatchString(part) && !studyPattern.MatchString(part)
+}
+
+// splitList splits a part at commas, dropping empty items.
+func splitList(part string) []string {
+	var items []string
+	for _, item := range strings.Split(part, ",") {
+		if item = strings.Trim(item, " ;:"); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitParts splits a heading line at separators such as "·", "|" and " at ".
+func splitParts(text string) []string {
+	var parts []string
+	for _, part := range separator.Split(text, -1) {
+		part = strings.Trim(part, " ,;:-–—·|")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// descriptionLines turns text lines into description lines, marking bullets with "- "
+// and joining lines that continue a wrapped bullet.
+func descriptionLines(lines []line) []string {
+	var out []string
+	previousBullet := false
+	for _, l := range lines {
+		text := l.text()
+		if isBullet(text) {
+			out = append(out, "- "+trimBullet(text))
+			previousBullet = true
+			continue
+		}
+
+		first, _ := utf8.DecodeRuneInString(text)
+		if previousBullet && unicode.IsLower(first) {
+			out[len(out)-1] += " " + text
+			continue
+		}
+		out = append(out, text)
+		previousBullet = false
+	}
+	return out
+}
+
+func isBullet(text string) bool {
+	return trimBullet(text) != text
+}
+
+func trimBullet(text string) string {
+	for _, marker := range bulletMarkers {
+		if rest, ok := strings.CutPrefix(text, marker); ok {
+			// Hyphens and asterisks only mark bullets when followed by a space.
+			if (marker == "-" || marker == "*") && !strings.HasPrefix(rest, " ") {
+				return text
+			}
+			return strings.TrimSpace(rest)
+		}
+	}
+	return text
+}
+
+// parseDate parses the dates rangePattern and datePattern match, rejecting numbers
+// that are unlikely to be years.
+func parseDate(value string) (time.Time, bool) {
+	value = strings.Join(strings.Fields(strings.Replace(value, ". ", " ", 1)), " ")
+	if strings.HasPrefix(strings.ToLower(value), "sept ") {
+		value = "Sep" + value[4:]
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			if t.Year() < 1950 || t.Year() > 2100 {
+				return time.Time{}, false
+			}
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,329 @@
+package pdfresume
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/export"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+var update = flag.Bool("update", false, "rewrite the sample PDFs in testdata")
+
+func yearMonth(year int, month time.Month) time.Time {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthPtr(year int, month time.Month) *time.Time {
+	d := yearMonth(year, month)
+	return &d
+}
+
+// exportedResume is the resume behind the samples exported with each template.
+func exportedResume(template string) *schema.ResumeResponseSchema {
+	return &schema.ResumeResponseSchema{
+		Title:       "Jane Doe",
+		Description: "Backend engineer who builds boring, reliable systems for payments and billing.",
+		Email:       "jane@x.dev",
+		URL:         "https://jane.dev",
+		Template:    template,
+		Skills:      []string{"Go", "PostgreSQL", "Kubernetes"},
+		Experiences: []schema.ExperienceResponseSchema{
+			{
+				Company:     "Acme Corp",
+				Title:       "Senior Engineer",
+				Location:    "Seoul, Korea",
+				StartDate:   yearMonth(2022, time.April),
+				Description: "- Led the billing platform rewrite\n- Cut p99 latency by 40%",
+			},
+			{
+				Company:     "Initech",
+				Title:       "Developer",
+				StartDate:   yearMonth(2018, time.March),
+				EndDate:     monthPtr(2022, time.March),
+				Description: "- Maintained the TPS report service",
+			},
+		},
+		Educations: []schema.EducationResponseSchema{
+			{
+				School:    "KAIST",
+				Degree:    "BSc",
+				Major:     "Computer Science",
+				StartDate: yearMonth(2014, time.March),
+				EndDate:   monthPtr(2018, time.February),
+				GPA:       "3.9/4.3",
+			},
+		},
+		Projects: []schema.ProjectResponseSchema{
+			{
+				Title:       "paperless",
+				Description: "Resume builder with PDF export.",
+				URL:         "https://github.com/jane/paperless",
+				StartDate:   yearMonth(2023, time.January),
+			},
+		},
+		UpdatedAt: yearMonth(2024, time.June),
+	}
+}
+
+// handBuiltLayout draws a resume the way word processors lay one out, with the
+// standard Helvetica fonts, dates aligned to the right margin and capitalized headings.
+func handBuiltLayout() ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "Letter", "")
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetCreationDate(yearMonth(2024, time.June))
+	pdf.SetModificationDate(yearMonth(2024, time.June))
+	pdf.AddPage()
+
+	heading := func(text string) {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(0, 7, text, "B", 1, "L", false, 0, "")
+		pdf.Ln(1)
+	}
+	entry := func(left, right string) {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(120, 6, left, "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, right, "", 1, "R", false, 0, "")
+	}
+	body := func(text string) {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 5, text, "", 1, "L", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", 22)
+	pdf.CellFormat(0, 10, "John Smith", "", 1, "L", false, 0, "")
+	body("john.smith@example.com | github.com/jsmith | (555) 123-4567")
+
+	heading("EXPERIENCE")
+	entry("Globex Inc.", "Jan 2020 - Present")
+	body("Staff Software Engineer")
+	body("- Designed the event pipeline handling 2M messages per second")
+	body("- Mentored six engineers")
+	entry("Hooli", "Jun 2016 - Dec 2019")
+	body("Software Engineer")
+	body("- Built the search ranking service")
+
+	heading("EDUCATION")
+	entry("Stanford University", "2012 - 2016")
+	body("B.S. in Computer Science, GPA 3.8")
+
+	heading("SKILLS")
+	body("Go, Java, Kafka, Kubernetes")
+
+	var b bytes.Buffer
+	err := pdf.Output(&b)
+	return b.Bytes(), err
+}
+
+// writeSamples renders the samples into testdata. Run go test with -update after
+// changing the templates or the layouts above.
+func writeSamples(t *testing.T) {
+	t.Helper()
+
+	for _, template := range []string{"modern", "classic", "minimal"} {
+		var b bytes.Buffer
+		if err := export.RenderPDF(&b, exportedResume(template)); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join("testdata", template+".pdf"), b.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := handBuiltLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("testdata", "handbuilt.pdf"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+var writeOnce sync.Once
+
+// readSample reads a sample from testdata, writing all samples first with -update.
+func readSample(t *testing.T, name string) []byte {
+	t.Helper()
+
+	if *update {
+		writeOnce.Do(func() { writeSamples(t) })
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", name+".pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// fields flattens the filled fields of resume, keyed by their JSON path like the
+// confidence map. Dates are written as months.
+func fields(resume *database.Resume) map[string]string {
+	f := make(map[string]string)
+	set := func(path, value string) {
+		if value != "" {
+			f[path] = value
+		}
+	}
+	setDates := func(prefix string, start time.Time, end *time.Time) {
+		if !start.IsZero() {
+			set(prefix+".startDate", start.Format("2006-01"))
+		}
+		if end != nil {
+			set(prefix+".endDate", end.Format("2006-01"))
+		}
+	}
+
+	set("title", resume.Title)
+	set("description", resume.Description)
+	set("email", resume.Email)
+	set("url", resume.URL)
+	set("skills", strings.Join(resume.Skills, ", "))
+	for i, e := range resume.Experiences {
+		prefix := fmt.Sprintf("experiences[%d]", i)
+		set(prefix+".company", e.Company)
+		set(prefix+".title", e.Title)
+		set(prefix+".location", e.Location)
+		set(prefix+".description", e.Description)
+		setDates(prefix, e.StartDate, e.EndDate)
+	}
+	for i, e := range resume.Educations {
+		prefix := fmt.Sprintf("educations[%d]", i)
+		set(prefix+".school", e.School)
+		set(prefix+".degree", e.Degree)
+		set(prefix+".major", e.Major)
+		set(prefix+".gpa", e.GPA)
+		set(prefix+".activities", e.Activities)
+		setDates(prefix, e.StartDate, e.EndDate)
+	}
+	for i, p := range resume.Projects {
+		prefix := fmt.Sprintf("projects[%d]", i)
+		set(prefix+".title", p.Title)
+		set(prefix+".description", p.Description)
+		set(prefix+".url", p.URL)
+		set(prefix+".skills", strings.Join(p.Skills, ", "))
+		setDates(prefix, p.StartDate, p.EndDate)
+	}
+	return f
+}
+
+// extracted is a field Decode is expected to fill, with its confidence.
+type extracted struct {
+	value      string
+	confidence float64
+}
+
+// exportedFields are read from the samples of every template.
+var exportedFields = map[string]extracted{
+	"title":                      {"Jane Doe", 0.9},
+	"description":                {"Backend engineer who builds boring, reliable systems for payments and billing.", 0.5},
+	"email":                      {"jane@x.dev", 0.95},
+	"url":                        {"https://jane.dev", 0.8},
+	"skills":                     {"Go, PostgreSQL, Kubernetes", 0.66},
+	"experiences[0].company":     {"Acme Corp", 0.76},
+	"experiences[0].title":       {"Senior Engineer", 0.76},
+	"experiences[0].location":    {"Seoul, Korea", 0.57},
+	"experiences[0].startDate":   {"2022-04", 0.86},
+	"experiences[0].description": {"- Led the billing platform rewrite\n- Cut p99 latency by 40%", 0.57},
+	"experiences[1].company":     {"Initech", 0.48},
+	"experiences[1].title":       {"Developer", 0.76},
+	"experiences[1].startDate":   {"2018-03", 0.86},
+	"experiences[1].endDate":     {"2022-03", 0.86},
+	"experiences[1].description": {"- Maintained the TPS report service", 0.57},
+	"educations[0].school":       {"KAIST", 0.48},
+	"educations[0].degree":       {"BSc", 0.76},
+	"educations[0].major":        {"Computer Science", 0.48},
+	"educations[0].gpa":          {"3.9/4.3", 0.86},
+	"educations[0].startDate":    {"2014-03", 0.86},
+	"educations[0].endDate":      {"2018-02", 0.86},
+}
+
+// projectFields are read from the samples of templates that show projects.
+var projectFields = map[string]extracted{
+	"projects[0].title":       {"paperless", 0.66},
+	"projects[0].description": {"Resume builder with PDF export.", 0.57},
+	"projects[0].url":         {"https://github.com/jane/paperless", 0.86},
+	"projects[0].startDate":   {"2023-01", 0.86},
+}
+
+func checkDecode(t *testing.T, data []byte, want map[string]extracted) {
+	t.Helper()
+
+	resume, warnings, confidence, err := Decode(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) > 0 {
+		t.Errorf("warnings = %+v, want none", warnings)
+	}
+
+	got := fields(resume)
+	for path, field := range want {
+		if got[path] != field.value {
+			t.Errorf("%s = %q, want %q", path, got[path], field.value)
+		}
+		if confidence[path] != field.confidence {
+			t.Errorf("confidence of %s = %v, want %v", path, confidence[path], field.confidence)
+		}
+	}
+	for path, value := range got {
+		if _, ok := want[path]; !ok {
+			t.Errorf("unexpected %s = %q", path, value)
+		}
+	}
+	for path := range confidence {
+		if _, ok := want[path]; !ok {
+			t.Errorf("confidence reported for unexpected %s", path)
+		}
+	}
+}
+
+func TestDecodeExportedResumes(t *testing.T) {
+	for _, template := range []string{"modern", "classic", "minimal"} {
+		t.Run(template, func(t *testing.T) {
+			want := maps.Clone(exportedFields)
+			// The minimal template leaves projects out.
+			if template != "minimal" {
+				maps.Copy(want, projectFields)
+			}
+			checkDecode(t, readSample(t, template), want)
+		})
+	}
+}
+
+func TestDecodeHandBuiltLayout(t *testing.T) {
+	checkDecode(t, readSample(t, "handbuilt"), map[string]extracted{
+		"title":                      {"John Smith", 0.9},
+		"email":                      {"john.smith@example.com", 0.95},
+		"url":                        {"https://github.com/jsmith", 0.6},
+		"skills":                     {"Go, Java, Kafka, Kubernetes", 0.66},
+		"experiences[0].company":     {"Globex Inc.", 0.76},
+		"experiences[0].title":       {"Staff Software Engineer", 0.76},
+		"experiences[0].startDate":   {"2020-01", 0.86},
+		"experiences[0].description": {"- Designed the event pipeline handling 2M messages per second\n- Mentored six engineers", 0.57},
+		"experiences[1].company":     {"Hooli", 0.48},
+		"experiences[1].title":       {"Software Engineer", 0.76},
+		"experiences[1].startDate":   {"2016-06", 0.86},
+		"experiences[1].endDate":     {"2019-12", 0.86},
+		"experiences[1].description": {"- Built the search ranking service", 0.57},
+		"educations[0].school":       {"Stanford University", 0.76},
+		"educations[0].degree":       {"B.S.", 0.76},
+		"educations[0].major":        {"Computer Science", 0.76},
+		"educations[0].gpa":          {"3.8", 0.86},
+		"educations[0].startDate":    {"2012-01", 0.86},
+		"educations[0].endDate":      {"2016-01", 0.86},
+	})
+}
@@ -0,0 +1,211 @@
+// Package pdfresume drafts a resume from the text of a PDF file. The text carries no
+// structure, so sections and entries are recognized from headings, dates and
+// emphasis, and every field is reported with how confident the guess is.
+package pdfresume
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// maxPages bounds how much of a file is read. Resumes rarely run past a few pages.
+const maxPages = 10
+
+// line is a row of text on a page. Text placed far apart on the same row, such as
+// a date aligned to the right margin, is kept in separate segments.
+type line struct {
+	segments []string
+	size     float64
+	bold     bool
+}
+
+func (l line) text() string {
+	return strings.Join(l.segments, " ")
+}
+
+// run is text drawn in one piece, without moving to another position.
+type run struct {
+	x, y, end float64
+	size      float64
+	bold      bool
+	text      strings.Builder
+}
+
+// extract reads the lines of the first maxPages pages in reading order, assuming a
+// single column. It reports the number of pages in the file.
+func extract(ctx context.Context, data []byte) (lines []line, pages int, err error) {
+	// The reader panics on malformed content streams instead of returning errors, and
+	// the content reader panics with abort to stop early.
+	defer func() {
+		if r := recover(); r != nil {
+			lines, pages, err = nil, 0, fmt.Errorf("%v", r)
+			if a, ok := r.(abort); ok {
+				err = a.err
+			}
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	content := newContentReader(ctx)
+	pages = reader.NumPage()
+	for i := 1; i <= min(pages, maxPages); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		lines = append(lines, pageLines(content.pageText(page))...)
+	}
+	return lines, pages, nil
+}
+
+// pageLines groups the characters of a page into runs, and runs on the same row into
+// lines from the top of the page down.
+func pageLines(texts []pdf.Text) []line {
+	var runs []*run
+	var current *run
+	for _, t := range texts {
+		if t.S == "" {
+			continue
+		}
+
+		continues := current != nil &&
+			math.Abs(t.Y-current.y) < t.FontSize*0.3 &&
+			math.Abs(t.X-current.end) < t.FontSize*0.2
+		if !continues {
+			current = &run{x: t.X, y: t.Y, size: t.FontSize, bold: isBold(t.Font)}
+			runs = append(runs, current)
+		}
+		current.text.WriteString(t.S)
+		current.end = t.X + t.W
+	}
+
+	type row struct {
+		y    float64
+		runs []*run
+	}
+	var rows []*row
+	for _, r := range runs {
+		if strings.TrimSpace(r.text.String()) == "" {
+			continue
+		}
+
+		var found *row
+		for _, candidate := range rows {
+			if math.Abs(candidate.y-r.y) < r.size*0.4 {
+				found = candidate
+				break
+			}
+		}
+		if found == nil {
+			found = &row{y: r.y}
+			rows = append(rows, found)
+		}
+		found.runs = append(found.runs, r)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].y > rows[j].y
+	})
+
+	lines := make([]line, 0, len(rows))
+	for _, row := range rows {
+		sort.SliceStable(row.runs, func(i, j int) bool {
+			return row.runs[i].x < row.runs[j].x
+		})
+
+		var l line
+		var segment strings.Builder
+		var previous *run
+		for _, r := range row.runs {
+			text := r.text.String()
+			if previous != nil {
+				// A gap wider than a few characters separates columns; a smaller one
+				// is a space drawn by moving the text position.
+				gap := r.x - previous.end
+				if gap > r.size*2 {
+					l.segments = appendSegment(l.segments, segment.String())
+					segment.Reset()
+				} else if gap > r.size*0.15 && !endsWithSpace(segment.String()) {
+					segment.WriteByte(' ')
+				}
+			}
+			segment.WriteString(text)
+
+			if r.size > l.size {
+				l.size = r.size
+			}
+			if strings.TrimSpace(text) != "" {
+				l.bold = l.bold || r.bold
+			}
+			previous = r
+		}
+		l.segments = appendSegment(l.segments, segment.String())
+
+		if len(l.segments) > 0 && !isPageNumber(l.text()) {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func appendSegment(segments []string, segment string) []string {
+	segment = strings.Join(strings.Fields(segment), " ")
+	if segment == "" {
+		return segments
+	}
+	return append(segments, segment)
+}
+
+func endsWithSpace(s string) bool {
+	return s == "" || unicode.IsSpace(rune(s[len(s)-1]))
+}
+
+// isBold guesses the weight from the font name, such as Helvetica-Bold or
+// Roboto-SemiBold. Fonts registered with a style suffix end in B, as in ArialB.
+func isBold(font string) bool {
+	lower := strings.ToLower(font)
+	for _, weight := range []string{"bold", "black", "heavy", "semibold"} {
+		if strings.Contains(lower, weight) {
+			return true
+		}
+	}
+	return strings.HasSuffix(font, "B") || strings.HasSuffix(font, "BI")
+}
+
+// isPageNumber reports footers like "2", "2 / 3" or "Page 2 of 3".
+func isPageNumber(text string) bool {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = strings.ReplaceAll(strings.TrimPrefix(text, "page "), " of ", "/")
+	for _, r := range text {
+		if !unicode.IsDigit(r) && r != '/' && r != ' ' {
+			return false
+		}
+	}
+	return text != ""
+}
+
+// bodySize is the font size most characters are set in.
+func bodySize(lines []line) float64 {
+	counts := make(map[float64]int)
+	for _, l := range lines {
+		counts[math.Round(l.size*2)/2] += len(l.text())
+	}
+
+	size, most := 0.0, 0
+	for s, count := range counts {
+		if count > most || count == most && s < size {
+			size, most = s, count
+		}
+	}
+	return size
+}
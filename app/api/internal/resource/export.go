@@ -0,0 +1,171 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/export"
+	"github.com/hwangseonu/paperless.dev/internal/importer"
+	"github.com/hwangseonu/paperless.dev/internal/jsonresume"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"github.com/hwangseonu/paperless.dev/internal/templates"
+)
+
+// ExportPDF *Resume.ExportPDF
+// @Summary	export resume as pdf
+// @Description	render the resume through its template into a paginated PDF with selectable text.
+// @Description	The same visibility rules as reading the resume apply.
+// @Tags	Resume
+// @Produce	application/pdf
+// @Param	id	path	string	true	"Resume ID"
+// @Success 200 {file}	file
+// @Failure 400 {object} 	schema.Error
+// @Failure 403 {object} 	schema.Error
+// @Failure 404 {object} 	schema.Error
+// @Failure 500 {object} 	schema.Error
+// @Router	/resumes/{id}/export.pdf [get]
+// @Security BearerAuth
+func (resource *Resume) ExportPDF(c *gin.Context) {
+	resume, _, err := resource.findReadable(c.Param("id"), c)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	exportPDF(c, resume.ResponseSchema())
+}
+
+// Export *Resume.Export
+// @Summary	export resume
+// @Description	export the resume in another format. pdf renders the resume through its template,
+// @Description	jsonresume produces a jsonresume.org document that can be imported again without loss,
+// @Description	md and txt produce Markdown and plain text and docx a Word document with a single column layout
+// @Description	that applicant tracking systems can parse. tex produces a LaTeX source for the moderncv class in the
+// @Description	style of the resume's template; with bundle=true it is zipped together with a class file that only
+// @Description	needs a basic LaTeX installation. These formats accept the sections to include in
+// @Description	order, the date format and the order of entries, and always produce the same output for the same
+// @Description	resume. The same visibility rules as reading the resume apply.
+// @Tags	Resume
+// @Produce	application/pdf
+// @Produce	json
+// @Produce	text/markdown
+// @Produce	text/plain
+// @Produce	application/vnd.openxmlformats-officedocument.wordprocessingml.document
+// @Produce	application/x-tex
+// @Produce	application/zip
+// @Param	id	path	string	true	"Resume ID"
+// @Param	format	query	string	false	"Export format"	Enums(pdf, jsonresume, md, txt, docx, tex)	default(pdf)
+// @Param	sections	query	string	false	"Comma separated sections to include, in order. Defaults to the template's sections"
+// @Param	dateFormat	query	string	false	"Date format"	Enums(short, long, iso, numeric, year)	default(short)
+// @Param	order	query	string	false	"Order of entries"	Enums(resume, recent)	default(resume)
+// @Param	bundle	query	bool	false	"Zip the LaTeX source with its class file"
+// @Success 200 {file}	file
+// @Failure 400 {object} 	schema.Error
+// @Failure 403 {object} 	schema.Error
+// @Failure 404 {object} 	schema.Error
+// @Failure 500 {object} 	schema.Error
+// @Router	/resumes/{id}/export [get]
+// @Security BearerAuth
+func (resource *Resume) Export(c *gin.Context) {
+	resume, _, err := resource.findReadable(c.Param("id"), c)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	switch c.DefaultQuery("format", "pdf") {
+	case "pdf":
+		exportPDF(c, resume.ResponseSchema())
+	case importer.FormatJSONResume:
+		exportJSONResume(c, resume)
+	case "md":
+		exportText(c, resume.ResponseSchema(), export.RenderMarkdown, "md", "text/markdown; charset=utf-8")
+	case "txt":
+		exportText(c, resume.ResponseSchema(), export.RenderText, "txt", "text/plain; charset=utf-8")
+	case "docx":
+		exportText(c, resume.ResponseSchema(), export.RenderDOCX, "docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document")
+	case "tex":
+		if c.Query("bundle") == "true" {
+			exportText(c, resume.ResponseSchema(), export.RenderLaTeXBundle, "zip", "application/zip")
+		} else {
+			exportText(c, resume.ResponseSchema(), export.RenderLaTeX, "tex", "application/x-tex; charset=utf-8")
+		}
+	default:
+		common.AbortWithError(c, common.ErrUnsupportedFormat)
+	}
+}
+
+func exportPDF(c *gin.Context, resume *schema.ResumeResponseSchema) {
+	var buf bytes.Buffer
+	if err := export.RenderPDF(&buf, resume); err != nil {
+		log.Println("an error occurred while rendering resume pdf", err)
+		common.AbortWithError(c, common.ErrExportFailed)
+		return
+	}
+
+	setAttachmentName(c, "inline", resume.Title, "pdf")
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// exportJSONResume writes the resume as indented JSON, so exports kept in git diff well.
+func exportJSONResume(c *gin.Context, resume *database.Resume) {
+	canonical := ""
+	if resume.Public {
+		canonical = publicURL("/r/" + resume.ID.Hex())
+	}
+
+	out, err := json.MarshalIndent(jsonresume.Encode(resume.ResponseSchema(), canonical), "", "  ")
+	if err != nil {
+		log.Println("an error occurred while encoding json resume", err)
+		common.AbortWithError(c, common.ErrExportFailed)
+		return
+	}
+
+	setAttachmentName(c, "attachment", resume.Title, "json")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", append(out, '\n'))
+}
+
+// exportText renders a format built from the resume's text with the options given as
+// query values.
+func exportText(c *gin.Context, resume *schema.ResumeResponseSchema, render func(io.Writer, *schema.ResumeResponseSchema, export.Options) error, extension, contentType string) {
+	opts, err := export.ParseOptions(templates.Resolve(resume.Template), c.Query("sections"), c.Query("dateFormat"), c.Query("order"))
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := render(&buf, resume, opts); err != nil {
+		log.Println("an error occurred while rendering resume "+extension, err)
+		common.AbortWithError(c, common.ErrExportFailed)
+		return
+	}
+
+	setAttachmentName(c, "attachment", resume.Title, extension)
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// setAttachmentName sets Content-Disposition with a file name derived from title.
+func setAttachmentName(c *gin.Context, disposition, title, extension string) {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, strings.TrimSpace(title))
+	name = strings.Trim(name, "-")
+	if name == "" {
+		name = "resume"
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": name + "." + extension}))
+}
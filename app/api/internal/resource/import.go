@@ -0,0 +1,150 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/auth"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/importer"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const maxImportSize = 10 << 20
+
+// importTimeout bounds how long reading an uploaded file may take.
+const importTimeout = 20 * time.Second
+
+// Import *Resume.Import
+// @Summary	import resume
+// @Description	create a resume from a file exported by another tool. The file is sent as the request body or as
+// @Description	the file field of a multipart form. Its format is detected from the content unless format is given.
+// @Description	Data that has no place in a resume is left out and listed in warnings, and rows that cannot be read
+// @Description	are listed in errors with their file and line.
+// @Description	With preview=true the resume is returned without being saved, so it can be reviewed first; sending
+// @Description	the same file again without preview saves it.
+// @Description	PDF files are drafted from their text layout; confidence lists each extracted field by its JSON path,
+// @Description	such as experiences[0].company, with a value between 0 and 1. It is empty for structured formats.
+// @Tags	Resume
+// @Accept	json
+// @Accept	multipart/form-data
+// @Accept	application/zip
+// @Accept	application/pdf
+// @Produce	json
+// @Param	format	query	string	false	"Import format"	Enums(jsonresume, linkedin, pdf)
+// @Param	preview	query	bool	false	"Return the resume without saving it"
+// @Param	file	formData	file	false	"File to import"
+// @Success	200	{object}	object{resume=schema.ResumeResponseSchema,format=string,warnings=[]schema.ImportWarning,errors=[]schema.ImportError,confidence=map[string]number}
+// @Success	201	{object}	object{resume=schema.ResumeResponseSchema,format=string,warnings=[]schema.ImportWarning,errors=[]schema.ImportError,confidence=map[string]number}
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Router	/resumes/import [post]
+// @Security     BearerAuth
+func (resource *Resume) Import(c *gin.Context) {
+	credentials := auth.MustGetUserCredentials(c)
+
+	ownerID, err := bson.ObjectIDFromHex(credentials.UserID)
+	if err != nil {
+		common.AbortWithError(c, common.ErrInvalidUserID)
+		return
+	}
+
+	data, err := readUpload(c)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), importTimeout)
+	defer cancel()
+
+	result, err := importer.Import(ctx, c.Query("format"), data)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	result.Resume.OwnerID = ownerID
+	if c.Query("preview") == "true" {
+		c.JSON(http.StatusOK, gin.H{
+			"resume":     result.Resume.ResponseSchema(),
+			"format":     result.Format,
+			"warnings":   result.Warnings,
+			"errors":     result.Errors,
+			"confidence": result.Confidence,
+		})
+		return
+	}
+
+	resume, err := resource.repository.Insert(result.Resume)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"resume":     resume.ResponseSchema(),
+		"format":     result.Format,
+		"warnings":   result.Warnings,
+		"errors":     result.Errors,
+		"confidence": result.Confidence,
+	})
+}
+
+// readUpload reads the uploaded file from the file field of a multipart form, or from
+// the request body otherwise.
+func readUpload(c *gin.Context) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	var r io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return nil, uploadError(err)
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return nil, uploadError(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, uploadError(err)
+	}
+	if len(data) == 0 {
+		return nil, common.NewValidationError(common.ErrInvalidImport, common.FieldError{
+			Field:   "file",
+			Reason:  "required",
+			Message: "no file was uploaded",
+		})
+	}
+	return data, nil
+}
+
+func uploadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return common.NewValidationError(common.ErrInvalidImport, common.FieldError{
+			Field:   "file",
+			Reason:  "too_large",
+			Message: fmt.Sprintf("must be at most %d bytes", maxImportSize),
+		})
+	}
+
+	return common.NewValidationError(common.ErrInvalidImport, common.FieldError{
+		Field:   "file",
+		Reason:  "unreadable",
+		Message: err.Error(),
+	})
+}
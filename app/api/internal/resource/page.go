@@ -0,0 +1,165 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/templates"
+)
+
+// maxSitemapURLs is the number of URLs a single sitemap may list.
+const maxSitemapURLs = 50000
+
+// maxCompiledTemplates is the number of custom templates kept compiled for public pages.
+const maxCompiledTemplates = 256
+
+// Page serves a public resume as a server-rendered HTML page at /r/:id, so link
+// previews and search engines see the same content as the web app.
+func (resource *Resume) Page(c *gin.Context) {
+	resume, err := resource.repository.FindByID(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidResumeID) {
+			err = common.ErrResumeNotFound
+		}
+		common.AbortWithError(c, err)
+		return
+	}
+
+	resource.renderPage(c, resume)
+}
+
+// PageBySlug serves a public resume at /u/:username/:slug. The page declares /r/:id as
+// its canonical URL, so changing the slug does not split search rankings.
+func (resource *Resume) PageBySlug(c *gin.Context) {
+	user, err := resource.userRepository.FindByUsername(c.Param("username"))
+	if err != nil {
+		if errors.Is(err, common.ErrUserNotFound) {
+			err = common.ErrResumeNotFound
+		}
+		common.AbortWithError(c, err)
+		return
+	}
+
+	resume, err := resource.repository.FindByOwnerAndSlug(user.ID.Hex(), c.Param("slug"))
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	resource.renderPage(c, resume)
+}
+
+// renderPage renders resume with its template. Private resumes are reported as missing
+// so their existence is not revealed. Custom templates are served with the sandbox's
+// Content-Security-Policy; if one can no longer be used, the default template is. When
+// the sandbox is busy the page is not served at all rather than in another template.
+func (resource *Resume) renderPage(c *gin.Context, resume *database.Resume) {
+	if !resume.Public {
+		common.AbortWithError(c, common.ErrResumeNotFound)
+		return
+	}
+
+	data := resume.ResponseSchema()
+	meta := templates.NewMeta(data, publicURL("/r/"+data.ID))
+
+	var buf bytes.Buffer
+	if sandboxed := resource.customTemplate(resume); sandboxed != nil {
+		err := sandboxed.RenderPage(&buf, data, meta)
+		if errors.Is(err, common.ErrSandboxBusy) {
+			common.AbortWithError(c, err)
+			return
+		}
+		if err == nil {
+			c.Header("Content-Security-Policy", templates.ContentSecurityPolicy)
+			c.Header("Cache-Control", "public, max-age=300")
+			c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+			return
+		}
+		log.Println("an error occurred while rendering custom template page", err)
+		buf.Reset()
+	}
+
+	if err := templates.Resolve(data.Template).RenderPage(&buf, data, meta); err != nil {
+		log.Println("an error occurred while rendering resume page", err)
+		common.AbortWithError(c, common.ErrExportFailed)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// customTemplate returns the resume's compiled custom template, or nil when the resume
+// uses a built-in one or its owner can no longer use the custom one.
+func (resource *Resume) customTemplate(resume *database.Resume) *templates.Sandboxed {
+	if resume.Template == "" {
+		return nil
+	}
+	if _, ok := templates.Lookup(resume.Template); ok {
+		return nil
+	}
+
+	template, err := resource.templateRepository.FindByID(resume.Template)
+	if err != nil || !template.ReadableBy(resume.OwnerID.Hex()) {
+		return nil
+	}
+
+	sandboxed, err := resource.compiledTemplates.Get(template.ID.Hex(), template.UpdatedAt, template.HTML, template.CSS)
+	if err != nil {
+		log.Println("an error occurred while compiling custom template", err)
+		return nil
+	}
+	return sandboxed
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// Sitemap lists the public pages of all public resumes, most recently updated first.
+func (resource *Resume) Sitemap(c *gin.Context) {
+	resumes, err := resource.repository.FindManyPublic(maxSitemapURLs)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	set := sitemapURLSet{
+		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		URLs:  make([]sitemapURL, 0, len(resumes)),
+	}
+	for _, resume := range resumes {
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     publicURL("/r/" + resume.ID.Hex()),
+			LastMod: resume.UpdatedAt.UTC().Format("2006-01-02"),
+		})
+	}
+
+	out, err := xml.Marshal(set)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), out...))
+}
+
+// publicURL returns the absolute URL of path on the public site.
+func publicURL(path string) string {
+	return strings.TrimSuffix(common.GetConfig().BaseURL, "/") + path
+}
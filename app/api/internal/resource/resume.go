@@ -2,24 +2,31 @@ package resource
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hwangseonu/paperless.dev/internal/auth"
 	"github.com/hwangseonu/paperless.dev/internal/common"
 	"github.com/hwangseonu/paperless.dev/internal/database"
 	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"github.com/hwangseonu/paperless.dev/internal/templates"
 )
 
 type Resume struct {
-	repository     database.ResumeRepository
-	userRepository database.UserRepository
+	repository         database.ResumeRepository
+	userRepository     database.UserRepository
+	templateRepository database.CustomTemplateRepository
+	compiledTemplates  *templates.Cache
 }
 
 func NewResume() *Resume {
 	return &Resume{
-		repository:     database.NewResumeRepository(),
-		userRepository: database.NewUserRepository(),
+		repository:         database.NewResumeRepository(),
+		userRepository:     database.NewUserRepository(),
+		templateRepository: database.NewCustomTemplateRepository(),
+		compiledTemplates:  templates.NewCache(maxCompiledTemplates),
 	}
 }
 
@@ -43,6 +50,8 @@ func (resource *Resume) RequestBody(method string) any {
 // @Param	resume body	schema.ResumeCreateSchema	true	"initial values of resume"
 // @Success	201	{object}	object{resume=schema.ResumeResponseSchema}
 // @Failure 400 {object}	schema.Error
+// @Failure 403 {object}	schema.Error
+// @Failure 409 {object}	schema.Error
 // @Failure 500 {object}	schema.Error
 // @Router	/resumes [post]
 // @Security     BearerAuth
@@ -52,6 +61,22 @@ func (resource *Resume) Create(body interface{}, c *gin.Context) (gin.H, int, er
 	createSchema := body.(*schema.ResumeCreateSchema)
 	createSchema.OwnerID = credentials.UserID
 
+	if err := resource.validateTemplate(createSchema.Template, credentials.UserID); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	if createSchema.Slug != "" {
+		if status, err := resource.validateSlug(createSchema.Slug, credentials.UserID, ""); err != nil {
+			return nil, status, err
+		}
+	}
+
+	if createSchema.Public {
+		if err := auth.EnsureEmailVerified(credentials.UserID); err != nil {
+			return nil, http.StatusForbidden, err
+		}
+	}
+
 	resume, err := resource.repository.Create(createSchema)
 
 	if err != nil {
@@ -75,6 +100,67 @@ func (resource *Resume) Create(body interface{}, c *gin.Context) (gin.H, int, er
 // @Router	/resumes/{id} [get]
 // @Security BearerAuth
 func (resource *Resume) Read(id string, c *gin.Context) (gin.H, int, error) {
+	resume, status, err := resource.findReadable(id, c)
+	if err != nil {
+		return nil, status, err
+	}
+
+	return gin.H{"resume": resume.ResponseSchema()}, http.StatusOK, nil
+}
+
+// validateTemplate accepts built-in template names and the IDs of custom templates
+// userID may use. An empty name selects the default template.
+func (resource *Resume) validateTemplate(name, userID string) error {
+	if name == "" {
+		return nil
+	}
+
+	if _, ok := templates.Lookup(name); ok {
+		return nil
+	}
+
+	template, err := resource.templateRepository.FindByID(name)
+	if err != nil && !errors.Is(err, common.ErrTemplateNotFound) {
+		return err
+	}
+	if err != nil || !template.ReadableBy(userID) {
+		return common.NewValidationError(common.ErrInvalidTemplate, common.FieldError{
+			Field:   "template",
+			Reason:  "unknown",
+			Message: fmt.Sprintf("template %q does not exist", name),
+		})
+	}
+	return nil
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// validateSlug checks that slug is well formed and not used by another resume of the
+// same owner, since public pages are addressed by username and slug.
+func (resource *Resume) validateSlug(slug, userID, resumeID string) (int, error) {
+	if !slugPattern.MatchString(slug) {
+		return http.StatusBadRequest, common.NewValidationError(common.ErrInvalidInput, common.FieldError{
+			Field:   "slug",
+			Reason:  "format",
+			Message: "slug may only contain lowercase letters, digits and single hyphens",
+		})
+	}
+
+	existing, err := resource.repository.FindByOwnerAndSlug(userID, slug)
+	if err != nil {
+		if errors.Is(err, common.ErrResumeNotFound) {
+			return http.StatusOK, nil
+		}
+		return http.StatusInternalServerError, err
+	}
+	if existing.ID.Hex() != resumeID {
+		return http.StatusConflict, common.ErrSlugConflict
+	}
+	return http.StatusOK, nil
+}
+
+// findReadable loads the resume if it is public or owned by the caller.
+func (resource *Resume) findReadable(id string, c *gin.Context) (*database.Resume, int, error) {
 	credentials := auth.GetUserCredentials(c)
 	userID := ""
 
@@ -93,7 +179,7 @@ func (resource *Resume) Read(id string, c *gin.Context) (gin.H, int, error) {
 	}
 
 	if resume.Public || resume.OwnerID.Hex() == userID {
-		return gin.H{"resume": resume.ResponseSchema()}, http.StatusOK, nil
+		return resume, http.StatusOK, nil
 	}
 
 	return nil, http.StatusForbidden, common.ErrAccessDenied
@@ -150,6 +236,7 @@ func (resource *Resume) ReadAll(c *gin.Context) (gin.H, int, error) {
 // @Failure 400 {object} 	schema.Error
 // @Failure 403 {object} 	schema.Error
 // @Failure 404 {object} 	schema.Error
+// @Failure 409 {object} 	schema.Error
 // @Failure 500 {object} 	schema.Error
 // @Router	/resumes/{id} [PATCH]
 // @Security     BearerAuth
@@ -174,6 +261,25 @@ func (resource *Resume) Update(id string, body interface{}, c *gin.Context) (gin
 	}
 
 	updateBody := body.(*schema.ResumeUpdateSchema)
+
+	if updateBody.Template != nil {
+		if err := resource.validateTemplate(*updateBody.Template, userID); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+
+	if updateBody.Slug != nil && *updateBody.Slug != "" && *updateBody.Slug != resumeDoc.Slug {
+		if status, err := resource.validateSlug(*updateBody.Slug, userID, id); err != nil {
+			return nil, status, err
+		}
+	}
+
+	if updateBody.Public != nil && *updateBody.Public && !resumeDoc.Public {
+		if err := auth.EnsureEmailVerified(userID); err != nil {
+			return nil, http.StatusForbidden, err
+		}
+	}
+
 	result, err := resource.repository.Update(id, updateBody)
 
 	if err != nil {
@@ -0,0 +1,329 @@
+package resource
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hwangseonu/paperless.dev/internal/auth"
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/database"
+	"github.com/hwangseonu/paperless.dev/internal/export"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+	"github.com/hwangseonu/paperless.dev/internal/templates"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type Template struct {
+	repository database.CustomTemplateRepository
+}
+
+func NewTemplate() *Template {
+	return &Template{
+		repository: database.NewCustomTemplateRepository(),
+	}
+}
+
+func (resource *Template) RequestBody(method string) any {
+	switch method {
+	case http.MethodPost:
+		return new(schema.TemplateCreateSchema)
+	case http.MethodPatch:
+		return new(schema.TemplateUpdateSchema)
+	default:
+		return nil
+	}
+}
+
+// Create *Template.Create
+// @Summary		create custom template
+// @Description	upload a template written in Go's html/template with CSS. The template renders the body of the
+// @Description	resume page with the resume as data. Templates are validated on upload and invalid ones are rejected
+// @Description	with the line and column of each problem. Template calls, call, printf, scripts, frames, forms and
+// @Description	external resources are not allowed.
+// @Tags	Template
+// @Accept	json
+// @Produce	json
+// @Param	template body	schema.TemplateCreateSchema	true	"template source"
+// @Success	201	{object}	object{template=schema.TemplateResponseSchema}
+// @Failure 400 {object}	schema.Error
+// @Failure 401 {object}	schema.Error
+// @Failure 500 {object}	schema.Error
+// @Failure 503 {object}	schema.Error
+// @Router	/templates [post]
+// @Security     BearerAuth
+func (resource *Template) Create(body interface{}, c *gin.Context) (gin.H, int, error) {
+	credentials := auth.MustGetUserCredentials(c)
+	createSchema := body.(*schema.TemplateCreateSchema)
+
+	if _, err := templates.Compile(createSchema.HTML, createSchema.CSS); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	ownerID, err := bson.ObjectIDFromHex(credentials.UserID)
+	if err != nil {
+		return nil, http.StatusBadRequest, common.ErrInvalidUserID
+	}
+
+	template, err := resource.repository.Create(&database.CustomTemplate{
+		OwnerID:     ownerID,
+		Name:        createSchema.Name,
+		Description: createSchema.Description,
+		HTML:        createSchema.HTML,
+		CSS:         createSchema.CSS,
+		Public:      createSchema.Public,
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return gin.H{"template": template.ResponseSchema()}, http.StatusCreated, nil
+}
+
+// Read *Template.Read
+// @Summary	get template
+// @Description	get a built-in template by name, or a custom template by ID. Private custom templates are only
+// @Description	visible to their owner.
+// @Tags	Template
+// @Produce	json
+// @Param	id	path	string	true	"Template name or custom template ID"
+// @Success 200 {object}	object{template=schema.TemplateResponseSchema}
+// @Failure 404 {object} 	schema.Error
+// @Failure 500 {object} 	schema.Error
+// @Router	/templates/{id} [get]
+// @Security BearerAuth
+func (resource *Template) Read(id string, c *gin.Context) (gin.H, int, error) {
+	if template, ok := templates.Lookup(id); ok {
+		return gin.H{"template": template.ResponseSchema()}, http.StatusOK, nil
+	}
+
+	template, status, err := resource.findReadable(id, c)
+	if err != nil {
+		return nil, status, err
+	}
+
+	return gin.H{"template": template.ResponseSchema()}, http.StatusOK, nil
+}
+
+// ReadAll *Template.ReadAll
+// @Summary	list templates
+// @Description	list the built-in templates followed by the caller's custom templates.
+// @Description	With shared=true, list the custom templates all users have shared instead.
+// @Tags	Template
+// @Produce	json
+// @Param	shared	query	bool	false	"List shared custom templates"
+// @Success 200 {object}	object{templates=[]schema.TemplateResponseSchema}
+// @Failure 500 {object} 	schema.Error
+// @Router	/templates [get]
+// @Security BearerAuth
+func (resource *Template) ReadAll(c *gin.Context) (gin.H, int, error) {
+	res := make([]*schema.TemplateResponseSchema, 0)
+
+	if c.Query("shared") == "true" {
+		shared, err := resource.repository.FindManyPublic()
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		for _, template := range shared {
+			res = append(res, template.ResponseSchema())
+		}
+		return gin.H{"templates": res}, http.StatusOK, nil
+	}
+
+	for _, template := range templates.All() {
+		res = append(res, template.ResponseSchema())
+	}
+
+	if credentials := auth.GetUserCredentials(c); credentials != nil {
+		owned, err := resource.repository.FindManyByOwnerID(credentials.UserID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		for _, template := range owned {
+			res = append(res, template.ResponseSchema())
+		}
+	}
+
+	return gin.H{"templates": res}, http.StatusOK, nil
+}
+
+// Update *Template.Update
+// @Summary	update custom template
+// @Description	update a custom template owned by the caller. Changed sources are validated again.
+// @Tags	Template
+// @Accept	json
+// @Produce	json
+// @Param	id	path	string	true	"Custom template ID"
+// @Param	template body	schema.TemplateUpdateSchema	true	"update values of template"
+// @Success 200 {object}	object{template=schema.TemplateResponseSchema}
+// @Failure 400 {object} 	schema.Error
+// @Failure 401 {object} 	schema.Error
+// @Failure 404 {object} 	schema.Error
+// @Failure 500 {object} 	schema.Error
+// @Failure 503 {object} 	schema.Error
+// @Router	/templates/{id} [PATCH]
+// @Security     BearerAuth
+func (resource *Template) Update(id string, body interface{}, c *gin.Context) (gin.H, int, error) {
+	if c.Request.Method == http.MethodPut {
+		return nil, http.StatusNotFound, nil
+	}
+
+	credentials := auth.MustGetUserCredentials(c)
+	updateBody := body.(*schema.TemplateUpdateSchema)
+
+	current, err := resource.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, common.ErrTemplateNotFound) {
+			return nil, http.StatusNotFound, common.ErrTemplateNotFound
+		}
+		return nil, http.StatusInternalServerError, common.ErrDatabase
+	}
+	if current.OwnerID.Hex() != credentials.UserID {
+		return nil, http.StatusNotFound, common.ErrTemplateNotFound
+	}
+
+	if updateBody.HTML != nil || updateBody.CSS != nil {
+		html, css := current.HTML, current.CSS
+		if updateBody.HTML != nil {
+			html = *updateBody.HTML
+		}
+		if updateBody.CSS != nil {
+			css = *updateBody.CSS
+		}
+		if _, err := templates.Compile(html, css); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+
+	template, err := resource.repository.Update(credentials.UserID, id, updateBody)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return gin.H{"template": template.ResponseSchema()}, http.StatusOK, nil
+}
+
+// Delete *Template.Delete
+// @Summary	delete custom template
+// @Description	delete a custom template owned by the caller. Resumes using it fall back to the default template.
+// @Tags	Template
+// @Produce	json
+// @Param	id	path	string	true	"Custom template ID"
+// @Success 204
+// @Failure 401 {object} 	schema.Error
+// @Failure 404 {object} 	schema.Error
+// @Failure 500 {object} 	schema.Error
+// @Router	/templates/{id} [DELETE]
+// @Security     BearerAuth
+func (resource *Template) Delete(id string, c *gin.Context) (gin.H, int, error) {
+	credentials := auth.MustGetUserCredentials(c)
+
+	if err := resource.repository.DeleteByID(credentials.UserID, id); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return nil, http.StatusNoContent, nil
+}
+
+// findReadable loads the custom template if it is public or owned by the caller.
+// Private templates of other users are reported as missing.
+func (resource *Template) findReadable(id string, c *gin.Context) (*database.CustomTemplate, int, error) {
+	userID := ""
+	if credentials := auth.GetUserCredentials(c); credentials != nil {
+		userID = credentials.UserID
+	}
+
+	template, err := resource.repository.FindByID(id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, common.ErrTemplateNotFound) {
+			status = http.StatusNotFound
+		}
+		return nil, status, err
+	}
+
+	if !template.ReadableBy(userID) {
+		return nil, http.StatusNotFound, common.ErrTemplateNotFound
+	}
+
+	return template, http.StatusOK, nil
+}
+
+// Preview *Template.Preview
+// @Summary	preview template
+// @Description	render sample resume data with the template. Built-in templates are rendered as PDF,
+// @Description	custom templates as a sandboxed HTML page.
+// @Tags	Template
+// @Produce	application/pdf
+// @Produce	text/html
+// @Param	id	path	string	true	"Template name or custom template ID"
+// @Success 200 {file}	file
+// @Failure 404 {object} 	schema.Error
+// @Failure 500 {object} 	schema.Error
+// @Failure 503 {object} 	schema.Error
+// @Router	/templates/{id}/preview [get]
+// @Security BearerAuth
+func (resource *Template) Preview(c *gin.Context) {
+	id := c.Param("id")
+
+	if template, ok := templates.Lookup(id); ok {
+		var buf bytes.Buffer
+		if err := export.RenderPDF(&buf, templates.SampleResume(template.Name)); err != nil {
+			log.Println("an error occurred while rendering template preview", err)
+			common.AbortWithError(c, common.ErrExportFailed)
+			return
+		}
+
+		setAttachmentName(c, "inline", template.Name+"-preview", "pdf")
+		c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+		return
+	}
+
+	template, _, err := resource.findReadable(id, c)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	sandboxed, err := templates.Compile(template.HTML, template.CSS)
+	if err != nil {
+		common.AbortWithError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := sandboxed.RenderPage(&buf, templates.SampleResume(template.ID.Hex()), nil); err != nil {
+		if errors.Is(err, common.ErrSandboxBusy) {
+			common.AbortWithError(c, err)
+			return
+		}
+		log.Println("an error occurred while rendering custom template preview", err)
+		common.AbortWithError(c, common.ErrExportFailed)
+		return
+	}
+
+	c.Header("Content-Security-Policy", templates.ContentSecurityPolicy)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// Thumbnail *Template.Thumbnail
+// @Summary	template thumbnail
+// @Description	get the SVG thumbnail of a built-in template
+// @Tags	Template
+// @Produce	image/svg+xml
+// @Param	id	path	string	true	"Template name"
+// @Success 200 {file}	file
+// @Failure 404 {object} 	schema.Error
+// @Router	/templates/{id}/thumbnail.svg [get]
+func (resource *Template) Thumbnail(c *gin.Context) {
+	thumbnail, ok := templates.Thumbnail(c.Param("id"))
+	if !ok {
+		common.AbortWithError(c, common.ErrTemplateNotFound)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "image/svg+xml", thumbnail)
+}
@@ -2,6 +2,7 @@ package resource
 
 import (
 	"errors"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,7 +11,6 @@ import (
 	"github.com/hwangseonu/paperless.dev/internal/common"
 	"github.com/hwangseonu/paperless.dev/internal/database"
 	"github.com/hwangseonu/paperless.dev/internal/schema"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type User struct {
@@ -56,9 +56,16 @@ func (resource *User) Create(body interface{}, _ *gin.Context) (gin.H, int, erro
 		return nil, http.StatusConflict, common.ErrUserConflict
 	}
 
-	var password []byte
-	password, _ = bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
-	user.Password = string(password)
+	if err := auth.CheckPassword(user.Password, user.Username, user.Email); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	password, err := auth.HashPassword(user.Password)
+	if err != nil {
+		log.Println("an error occurred while hashing password", err)
+		return nil, http.StatusInternalServerError, common.ErrInternal
+	}
+	user.Password = password
 
 	result, err := resource.repository.Create(user)
 
@@ -66,6 +73,10 @@ func (resource *User) Create(body interface{}, _ *gin.Context) (gin.H, int, erro
 		return nil, http.StatusInternalServerError, common.ErrDatabase
 	}
 
+	if err := auth.SendVerificationEmail(result); err != nil {
+		log.Println("an error occurred while sending verification email", err)
+	}
+
 	return gin.H{
 		"id":       result.ID,
 		"username": user.Username,
@@ -133,6 +144,18 @@ func (resource *User) Update(id string, body interface{}, c *gin.Context) (gin.H
 	targetID := credentials.UserID
 	updateSchema := body.(*schema.UserUpdateSchema)
 
+	currentUser, err := resource.repository.FindByID(targetID)
+	if err != nil {
+		if errors.Is(err, common.ErrUserNotFound) {
+			return nil, http.StatusNotFound, common.ErrUserNotFound
+		}
+		return nil, http.StatusInternalServerError, common.ErrDatabase
+	}
+
+	if updateSchema.Email != nil && *updateSchema.Email == currentUser.Email {
+		updateSchema.Email = nil
+	}
+
 	updatedUser, err := resource.repository.Update(targetID, updateSchema)
 	if err != nil {
 		if errors.Is(err, common.ErrUserNotFound) {
@@ -141,6 +164,15 @@ func (resource *User) Update(id string, body interface{}, c *gin.Context) (gin.H
 		return nil, http.StatusInternalServerError, common.ErrDatabase
 	}
 
+	if updateSchema.Email != nil {
+		if err := auth.RevokeMagicLinks(updatedUser.ID.Hex()); err != nil {
+			log.Println("an error occurred while revoking magic links", err)
+		}
+		if err := auth.SendVerificationEmail(updatedUser); err != nil {
+			log.Println("an error occurred while sending verification email", err)
+		}
+	}
+
 	return gin.H{
 		"user": updatedUser.ResponseSchema(),
 	}, http.StatusOK, nil
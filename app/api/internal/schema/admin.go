@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"time"
+)
+
+type ImpersonationCreateSchema struct {
+	UserID      string `json:"userId" binding:"required"`
+	Reason      string `json:"reason" binding:"required,min=1,max=500"`
+	AllowWrites bool   `json:"allowWrites"`
+}
+
+type ImpersonationResponseSchema struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	AllowWrites bool      `json:"allowWrites"`
+}
+
+type AuditLogResponseSchema struct {
+	ID        string    `json:"id"`
+	ActorID   string    `json:"actorId"`
+	SubjectID string    `json:"subjectId"`
+	Action    string    `json:"action"`
+	Reason    string    `json:"reason,omitempty"`
+	Method    string    `json:"method,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Status    int       `json:"status,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+}
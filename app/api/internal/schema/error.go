@@ -1,8 +1,17 @@
 package schema
 
+type FieldError struct {
+	Field   string `json:"field"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
 type Error struct {
 	Error struct {
-		Message string `json:"message"`
-		Code    int    `json:"code"`
+		Message string       `json:"message"`
+		Code    int          `json:"code"`
+		Fields  []FieldError `json:"fields,omitempty"`
 	} `json:"error"`
 }
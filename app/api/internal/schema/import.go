@@ -0,0 +1,17 @@
+package schema
+
+// ImportWarning reports data in an imported file that could not be mapped onto the
+// resume and was left out.
+type ImportWarning struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ImportError reports a row of an imported file that could not be read completely. The
+// row is left out, or imported without the field, depending on what is missing.
+type ImportError struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
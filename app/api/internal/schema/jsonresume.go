@@ -0,0 +1,63 @@
+package schema
+
+// JSONResume is the subset of the jsonresume.org schema that maps onto a resume.
+// Dates are ISO 8601 strings: YYYY-MM-DD, YYYY-MM or YYYY.
+type JSONResume struct {
+	Schema    string                `json:"$schema,omitempty"`
+	Basics    JSONResumeBasics      `json:"basics"`
+	Work      []JSONResumeWork      `json:"work,omitempty"`
+	Education []JSONResumeEducation `json:"education,omitempty"`
+	Projects  []JSONResumeProject   `json:"projects,omitempty"`
+	Skills    []JSONResumeSkill     `json:"skills,omitempty"`
+	Meta      *JSONResumeMeta       `json:"meta,omitempty"`
+}
+
+type JSONResumeBasics struct {
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Image   string `json:"image,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type JSONResumeWork struct {
+	Name       string   `json:"name,omitempty"`
+	Position   string   `json:"position,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+// JSONResumeEducation carries activities as an extension field, since the schema has
+// no equivalent and dropping it would lose data on a round trip.
+type JSONResumeEducation struct {
+	Institution string `json:"institution,omitempty"`
+	StudyType   string `json:"studyType,omitempty"`
+	Area        string `json:"area,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+	Score       string `json:"score,omitempty"`
+	Activities  string `json:"activities,omitempty"`
+}
+
+type JSONResumeProject struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+}
+
+type JSONResumeSkill struct {
+	Name string `json:"name"`
+}
+
+type JSONResumeMeta struct {
+	Canonical    string `json:"canonical,omitempty"`
+	Version      string `json:"version,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
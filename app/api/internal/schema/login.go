@@ -0,0 +1,16 @@
+package schema
+
+import (
+	"time"
+)
+
+type LoginResponseSchema struct {
+	ID        string    `json:"id"`
+	Method    string    `json:"method"`
+	Success   bool      `json:"success"`
+	Failure   string    `json:"failure,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	NewDevice bool      `json:"newDevice"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"time"
+)
+
+type OAuthClientCreateSchema struct {
+	Name         string   `json:"name" binding:"required,min=1,max=100"`
+	Website      string   `json:"website" binding:"omitempty,url"`
+	RedirectURIs []string `json:"redirectUris" binding:"required,min=1,max=10,dive,url"`
+	Scopes       []string `json:"scopes" binding:"required,min=1"`
+	Confidential bool     `json:"confidential"`
+}
+
+type OAuthClientResponseSchema struct {
+	ID           string    `json:"id"`
+	ClientID     string    `json:"clientId"`
+	ClientSecret string    `json:"clientSecret,omitempty"`
+	Name         string    `json:"name"`
+	Website      string    `json:"website,omitempty"`
+	RedirectURIs []string  `json:"redirectUris"`
+	Scopes       []string  `json:"scopes"`
+	Confidential bool      `json:"confidential"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// OAuthConsentSchema describes an authorization request so the web app can render the
+// consent screen.
+type OAuthConsentSchema struct {
+	Client          OAuthConsentClientSchema `json:"client"`
+	Scopes          []string                 `json:"scopes"`
+	RedirectURI     string                   `json:"redirectUri"`
+	State           string                   `json:"state,omitempty"`
+	AlreadyApproved bool                     `json:"alreadyApproved"`
+}
+
+type OAuthConsentClientSchema struct {
+	ClientID string `json:"clientId"`
+	Name     string `json:"name"`
+	Website  string `json:"website,omitempty"`
+}
+
+type OAuthDecisionSchema struct {
+	ClientID            string `json:"client_id" binding:"required"`
+	RedirectURI         string `json:"redirect_uri" binding:"required"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge" binding:"required"`
+	CodeChallengeMethod string `json:"code_challenge_method" binding:"required"`
+	Approve             bool   `json:"approve"`
+}
+
+type OAuthRedirectSchema struct {
+	RedirectURI string `json:"redirectUri"`
+}
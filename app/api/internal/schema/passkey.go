@@ -0,0 +1,14 @@
+package schema
+
+import (
+	"time"
+)
+
+type PasskeyResponseSchema struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	BackupEligible bool       `json:"backupEligible"`
+	SignCount      uint32     `json:"signCount"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
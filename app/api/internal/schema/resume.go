@@ -9,6 +9,7 @@ type ResumeCreateSchema struct {
 	Description string `json:"description,omitempty"`
 	Public      bool   `json:"public,omitempty"`
 	Template    string `json:"template,omitempty"`
+	Slug        string `json:"slug,omitempty" binding:"omitempty,max=64"`
 	OwnerID     string `json:"-"`
 }
 
@@ -52,6 +53,7 @@ type ResumeResponseSchema struct {
 	URL         string                     `json:"url,omitempty"`
 	Public      bool                       `json:"public"`
 	Template    string                     `json:"template,omitempty"`
+	Slug        string                     `json:"slug,omitempty"`
 	Skills      []string                   `json:"skills,omitempty"`
 	Experiences []ExperienceResponseSchema `json:"experiences,omitempty"`
 	Educations  []EducationResponseSchema  `json:"educations,omitempty"`
@@ -96,6 +98,7 @@ type ResumeUpdateSchema struct {
 	Image       *string                   `json:"image,omitempty"`
 	Public      *bool                     `json:"public,omitempty"`
 	Template    *string                   `json:"template,omitempty"`
+	Slug        *string                   `json:"slug,omitempty" binding:"omitempty,max=64"`
 	Skills      *[]string                 `json:"skills,omitempty"`
 	Experiences *[]ExperienceUpdateSchema `json:"experiences,omitempty"`
 	Educations  *[]EducationUpdateSchema  `json:"educations,omitempty"`
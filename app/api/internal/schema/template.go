@@ -0,0 +1,37 @@
+package schema
+
+import "time"
+
+type TemplateCreateSchema struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description,omitempty"`
+	HTML        string `json:"html" binding:"required"`
+	CSS         string `json:"css,omitempty"`
+	Public      bool   `json:"public,omitempty"`
+}
+
+type TemplateUpdateSchema struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty"`
+	HTML        *string `json:"html,omitempty"`
+	CSS         *string `json:"css,omitempty"`
+	Public      *bool   `json:"public,omitempty"`
+}
+
+// TemplateResponseSchema describes a built-in template, identified by its name, or a
+// custom template, identified by its ID and carrying its source.
+type TemplateResponseSchema struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Custom      bool       `json:"custom"`
+	Sections    []string   `json:"sections,omitempty"`
+	PageSize    string     `json:"pageSize,omitempty"`
+	Thumbnail   string     `json:"thumbnail,omitempty"`
+	OwnerID     string     `json:"ownerId,omitempty"`
+	Public      bool       `json:"public,omitempty"`
+	HTML        string     `json:"html,omitempty"`
+	CSS         string     `json:"css,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,56 @@
+package templates
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache keeps compiled custom templates so that serving a page does not compile its
+// template again. Entries are keyed by template ID and replaced when the template's
+// updatedAt changes.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	size    int
+}
+
+type cacheEntry struct {
+	updatedAt time.Time
+	sandboxed *Sandboxed
+}
+
+func NewCache(size int) *Cache {
+	return &Cache{
+		entries: make(map[string]cacheEntry),
+		size:    size,
+	}
+}
+
+// Get returns the compiled template with the given ID and source. Templates are compiled
+// when they are saved, so a miss only repeats the checks and skips the trial render.
+func (c *Cache) Get(id string, updatedAt time.Time, source, css string) (*Sandboxed, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	c.mu.Unlock()
+	if ok && entry.updatedAt.Equal(updatedAt) {
+		return entry.sandboxed, nil
+	}
+
+	sandboxed, err := validate(source, css)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[id]; !ok && len(c.entries) >= c.size {
+		// Any entry will do; evicted templates are cheap to validate again.
+		for key := range c.entries {
+			delete(c.entries, key)
+			break
+		}
+	}
+	c.entries[id] = cacheEntry{updatedAt: updatedAt, sandboxed: sandboxed}
+	return sandboxed, nil
+}
@@ -0,0 +1,212 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+//go:embed html
+var builtinHTML embed.FS
+
+var builtinBody = template.Must(template.New("resume.html").Funcs(sandboxFuncs).ParseFS(builtinHTML, "html/resume.html"))
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+{{- with .Meta}}
+<meta name="description" content="{{.Description}}">
+<link rel="canonical" href="{{.URL}}">
+<meta property="og:type" content="profile">
+<meta property="og:site_name" content="paperless.dev">
+<meta property="og:title" content="{{$.Title}}">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:url" content="{{.URL}}">
+{{- if .Image}}
+<meta property="og:image" content="{{.Image}}">
+{{- end}}
+<meta name="twitter:card" content="summary">
+<meta name="twitter:title" content="{{$.Title}}">
+<meta name="twitter:description" content="{{.Description}}">
+{{- if .Image}}
+<meta name="twitter:image" content="{{.Image}}">
+{{- end}}
+<script type="application/ld+json">{{.Person}}</script>
+{{- end}}
+<style>{{.CSS}}</style>
+</head>
+<body>
+{{.Body}}
+</body>
+</html>
+`))
+
+type page struct {
+	Title string
+	Meta  *Meta
+	CSS   template.CSS
+	Body  template.HTML
+}
+
+// maxDescription is the length in characters link previews reliably show.
+const maxDescription = 200
+
+// Meta describes a published resume to link previews and search engines. Pages
+// rendered without it, such as template previews, have no such tags.
+type Meta struct {
+	URL         string
+	Description string
+	Image       string
+	Person      *Person
+}
+
+// Person is the schema.org Person embedded in public pages as JSON-LD.
+type Person struct {
+	Context     string          `json:"@context"`
+	Type        string          `json:"@type"`
+	Name        string          `json:"name"`
+	URL         string          `json:"url"`
+	Description string          `json:"description,omitempty"`
+	Image       string          `json:"image,omitempty"`
+	Email       string          `json:"email,omitempty"`
+	SameAs      []string        `json:"sameAs,omitempty"`
+	JobTitle    string          `json:"jobTitle,omitempty"`
+	WorksFor    *Organization   `json:"worksFor,omitempty"`
+	AlumniOf    []*Organization `json:"alumniOf,omitempty"`
+	KnowsAbout  []string        `json:"knowsAbout,omitempty"`
+}
+
+type Organization struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+// NewMeta builds the page metadata of resume published at pageURL. A relative resume
+// image is resolved against pageURL, since previews require absolute URLs.
+func NewMeta(resume *schema.ResumeResponseSchema, pageURL string) *Meta {
+	meta := &Meta{
+		URL:         pageURL,
+		Description: summarize(resume),
+		Image:       absoluteURL(pageURL, resume.Image),
+	}
+
+	person := &Person{
+		Context:     "https://schema.org",
+		Type:        "Person",
+		Name:        resume.Title,
+		URL:         pageURL,
+		Description: meta.Description,
+		Image:       meta.Image,
+		Email:       resume.Email,
+		KnowsAbout:  resume.Skills,
+	}
+	if resume.URL != "" {
+		person.SameAs = []string{resume.URL}
+	}
+	for _, experience := range resume.Experiences {
+		if experience.EndDate == nil {
+			person.JobTitle = experience.Title
+			if experience.Company != "" {
+				person.WorksFor = &Organization{Type: "Organization", Name: experience.Company}
+			}
+			break
+		}
+	}
+	for _, education := range resume.Educations {
+		if education.School != "" {
+			person.AlumniOf = append(person.AlumniOf, &Organization{Type: "EducationalOrganization", Name: education.School})
+		}
+	}
+
+	meta.Person = person
+	return meta
+}
+
+// summarize returns the resume description on a single line, shortened to
+// maxDescription characters, or a generated summary when there is none.
+func summarize(resume *schema.ResumeResponseSchema) string {
+	description := strings.Join(strings.Fields(resume.Description), " ")
+	if description == "" {
+		description = "Resume of " + resume.Title
+		if len(resume.Skills) > 0 {
+			description += ". Skills: " + strings.Join(resume.Skills, ", ")
+		}
+	}
+
+	if utf8.RuneCountInString(description) <= maxDescription {
+		return description
+	}
+
+	runes := []rune(description)[:maxDescription-1]
+	return strings.TrimSpace(string(runes)) + "…"
+}
+
+func absoluteURL(base, ref string) string {
+	if ref == "" {
+		return ""
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+
+	resolved := baseURL.ResolveReference(refURL)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+	return resolved.String()
+}
+
+// RenderPage renders resume into a complete HTML document. meta may be nil.
+func (s *Sandboxed) RenderPage(w io.Writer, resume *schema.ResumeResponseSchema, meta *Meta) error {
+	var body bytes.Buffer
+	if err := s.RenderBody(&body, resume); err != nil {
+		return err
+	}
+
+	return writePage(w, resume.Title, meta, string(s.css), body.String())
+}
+
+// RenderPage renders resume into a complete HTML document with the built-in template's
+// sections and stylesheet. meta may be nil.
+func (template *Template) RenderPage(w io.Writer, resume *schema.ResumeResponseSchema, meta *Meta) error {
+	css, err := builtinHTML.ReadFile("html/" + template.Name + ".css")
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	err = builtinBody.Execute(&body, struct {
+		*schema.ResumeResponseSchema
+		Sections []string
+	}{resume, template.Sections})
+	if err != nil {
+		return err
+	}
+
+	return writePage(w, resume.Title, meta, string(css), body.String())
+}
+
+// writePage wraps a rendered body, which must already be escaped, in the page document.
+func writePage(w io.Writer, title string, meta *Meta, css, body string) error {
+	return pageTemplate.Execute(w, page{
+		Title: title,
+		Meta:  meta,
+		CSS:   template.CSS(css),
+		Body:  template.HTML(body),
+	})
+}
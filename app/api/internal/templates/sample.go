@@ -0,0 +1,68 @@
+package templates
+
+import (
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+func date(year int, month time.Month) time.Time {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func datePtr(year int, month time.Month) *time.Time {
+	d := date(year, month)
+	return &d
+}
+
+// SampleResume returns fixed example data for previewing the named template.
+func SampleResume(name string) *schema.ResumeResponseSchema {
+	return &schema.ResumeResponseSchema{
+		ID:          "sample",
+		Title:       "Jordan Lee — Backend Engineer",
+		Description: "Backend engineer with eight years of experience building reliable APIs and data pipelines.",
+		Email:       "jordan.lee@example.com",
+		URL:         "https://example.com/jordan",
+		Public:      true,
+		Template:    name,
+		Skills:      []string{"Go", "PostgreSQL", "MongoDB", "Kubernetes", "gRPC", "Terraform"},
+		Experiences: []schema.ExperienceResponseSchema{
+			{
+				Company:     "Acme Payments",
+				Title:       "Senior Backend Engineer",
+				Location:    "Seoul",
+				StartDate:   date(2021, time.March),
+				Description: "- Led the migration of the ledger service to an event sourced design\n- Cut p99 latency of the payments API from 800ms to 120ms",
+			},
+			{
+				Company:     "Globex",
+				Title:       "Software Engineer",
+				Location:    "Remote",
+				StartDate:   date(2017, time.July),
+				EndDate:     datePtr(2021, time.February),
+				Description: "- Built the internal deployment platform used by 40 teams\n- Maintained the public REST API and its client libraries",
+			},
+		},
+		Educations: []schema.EducationResponseSchema{
+			{
+				School:    "Example University",
+				Degree:    "B.S.",
+				Major:     "Computer Science",
+				StartDate: date(2013, time.March),
+				EndDate:   datePtr(2017, time.February),
+				GPA:       "3.9/4.3",
+			},
+		},
+		Projects: []schema.ProjectResponseSchema{
+			{
+				Title:       "paperless.dev",
+				Description: "Open source resume builder with PDF export.",
+				URL:         "https://example.com/paperless",
+				StartDate:   date(2024, time.January),
+				Skills:      []string{"Go", "React"},
+			},
+		},
+		CreatedAt: date(2025, time.January),
+		UpdatedAt: date(2025, time.January),
+	}
+}
@@ -0,0 +1,399 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template/parse"
+	"time"
+
+	"github.com/hwangseonu/paperless.dev/internal/common"
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+const (
+	maxSourceSize = 64 << 10
+	maxCSSSize    = 64 << 10
+	maxOutputSize = 1 << 20
+	renderTimeout = 2 * time.Second
+
+	// maxRangeDepth and maxNumber bound the work a template can do without writing
+	// any output, which the output writer cannot interrupt.
+	maxRangeDepth = 3
+	maxNumber     = 100
+
+	maxConcurrentRenders = 8
+)
+
+const sandboxName = "template"
+
+var (
+	errRenderTimeout  = errors.New("template execution timed out")
+	errOutputTooLarge = fmt.Errorf("template output exceeds %d bytes", maxOutputSize)
+)
+
+// renderSlots limits concurrent renders. A render that times out keeps its slot until
+// it actually finishes, so runaway templates cannot pile up goroutines.
+var renderSlots = make(chan struct{}, maxConcurrentRenders)
+
+// forbiddenFuncs are text/template builtins that are shadowed in the sandbox. call
+// invokes arbitrary function values and printf can allocate unbounded padding.
+var forbiddenFuncs = []string{"call", "printf"}
+
+// sandboxFuncs is the complete function map available to user templates. None of the
+// functions touch the file system or the network.
+var sandboxFuncs = template.FuncMap{
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"trim":      strings.TrimSpace,
+	"join":      strings.Join,
+	"contains":  strings.Contains,
+	"hasPrefix": strings.HasPrefix,
+	"lines":     lines,
+	"bullet":    bullet,
+	"date":      formatDate,
+	"dateRange": dateRange,
+	"default":   defaultString,
+	"call":      forbidden("call"),
+	"printf":    forbidden("printf"),
+}
+
+// Sandboxed is a user template that passed validation. It renders the body of a resume
+// page; the CSS is kept separately so callers can place it in the document head.
+type Sandboxed struct {
+	tmpl *template.Template
+	css  template.CSS
+}
+
+// Compile parses and validates a user template. Problems are reported as a
+// ValidationError whose fields carry the line and column of the offending code.
+func Compile(source, css string) (*Sandboxed, error) {
+	sandboxed, err := validate(source, css)
+	if err != nil {
+		return nil, err
+	}
+
+	// A trial render surfaces escaping and execution errors, such as unknown fields,
+	// while the author can still fix them.
+	if err := sandboxed.RenderBody(io.Discard, SampleResume("")); err != nil {
+		if errors.Is(err, common.ErrSandboxBusy) {
+			return nil, err
+		}
+		return nil, invalidTemplate(sourceError("execution", err))
+	}
+
+	return sandboxed, nil
+}
+
+// validate runs the checks of Compile that need no render. Templates that were compiled
+// when they were saved only need these again.
+func validate(source, css string) (*Sandboxed, error) {
+	if len(source) > maxSourceSize {
+		return nil, invalidTemplate(common.FieldError{Field: "html", Reason: "too_large", Message: fmt.Sprintf("must be at most %d bytes", maxSourceSize)})
+	}
+	if err := checkCSS(css); err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(sandboxName).Funcs(sandboxFuncs).Parse(source)
+	if err != nil {
+		return nil, invalidTemplate(sourceError("syntax", err))
+	}
+
+	if len(tmpl.Templates()) > 1 {
+		return nil, invalidTemplate(common.FieldError{Field: "html", Reason: "forbidden", Message: "define and block are not allowed"})
+	}
+	if tmpl.Tree == nil {
+		return nil, invalidTemplate(common.FieldError{Field: "html", Reason: "syntax", Message: "template is empty"})
+	}
+	if field, ok := checkTree(tmpl.Tree, tmpl.Tree.Root, 0); !ok {
+		return nil, invalidTemplate(field)
+	}
+
+	return &Sandboxed{tmpl: tmpl, css: template.CSS(css)}, nil
+}
+
+func (s *Sandboxed) CSS() template.CSS {
+	return s.css
+}
+
+// RenderBody executes the template with the sandbox's time and output limits.
+func (s *Sandboxed) RenderBody(w io.Writer, resume *schema.ResumeResponseSchema) error {
+	select {
+	case renderSlots <- struct{}{}:
+	default:
+		return common.ErrSandboxBusy
+	}
+
+	out := &limitedWriter{remaining: maxOutputSize, deadline: time.Now().Add(renderTimeout)}
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() { <-renderSlots }()
+		done <- s.tmpl.Execute(out, resume)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out.buf.Bytes())
+		return err
+	case <-time.After(renderTimeout):
+		return errRenderTimeout
+	}
+}
+
+// limitedWriter buffers output and fails once the size limit or the deadline is passed,
+// which makes the template stop at its next write.
+type limitedWriter struct {
+	buf       bytes.Buffer
+	remaining int
+	deadline  time.Time
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if time.Now().After(w.deadline) {
+		return 0, errRenderTimeout
+	}
+	if len(p) > w.remaining {
+		return 0, errOutputTooLarge
+	}
+
+	w.remaining -= len(p)
+	return w.buf.Write(p)
+}
+
+// checkTree rejects nested template calls, deeply nested ranges, large number literals
+// and forbidden functions.
+func checkTree(tree *parse.Tree, node parse.Node, rangeDepth int) (common.FieldError, bool) {
+	reject := func(n parse.Node, message string) (common.FieldError, bool) {
+		line, column := nodePosition(tree, n)
+		return common.FieldError{Field: "html", Reason: "forbidden", Message: message, Line: line, Column: column}, false
+	}
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return common.FieldError{}, true
+		}
+		for _, child := range n.Nodes {
+			if field, ok := checkTree(tree, child, rangeDepth); !ok {
+				return field, false
+			}
+		}
+	case *parse.ActionNode:
+		return checkTree(tree, n.Pipe, rangeDepth)
+	case *parse.IfNode:
+		return checkBranch(tree, &n.BranchNode, rangeDepth)
+	case *parse.WithNode:
+		return checkBranch(tree, &n.BranchNode, rangeDepth)
+	case *parse.RangeNode:
+		if rangeDepth+1 > maxRangeDepth {
+			return reject(n, fmt.Sprintf("range may be nested at most %d levels deep", maxRangeDepth))
+		}
+		return checkBranch(tree, &n.BranchNode, rangeDepth+1)
+	case *parse.TemplateNode:
+		return reject(n, "template calls are not allowed")
+	case *parse.TextNode:
+		if loc := forbiddenMarkup.FindIndex(n.Text); loc != nil {
+			line, column := nodePosition(tree, n)
+			prefix := n.Text[:loc[0]]
+			if newlines := bytes.Count(prefix, []byte("\n")); newlines > 0 {
+				line += newlines
+				column = len(prefix) - bytes.LastIndexByte(prefix, '\n')
+			} else {
+				column += len(prefix)
+			}
+			message := fmt.Sprintf("%q is not allowed", strings.TrimSpace(string(n.Text[loc[0]:loc[1]])))
+			return common.FieldError{Field: "html", Reason: "forbidden", Message: message, Line: line, Column: column}, false
+		}
+	case *parse.PipeNode:
+		if n == nil {
+			return common.FieldError{}, true
+		}
+		for _, cmd := range n.Cmds {
+			if field, ok := checkTree(tree, cmd, rangeDepth); !ok {
+				return field, false
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if field, ok := checkTree(tree, arg, rangeDepth); !ok {
+				return field, false
+			}
+		}
+	case *parse.ChainNode:
+		return checkTree(tree, n.Node, rangeDepth)
+	case *parse.IdentifierNode:
+		for _, name := range forbiddenFuncs {
+			if n.Ident == name {
+				return reject(n, fmt.Sprintf("function %q is not allowed", name))
+			}
+		}
+	case *parse.NumberNode:
+		if !n.IsInt || n.Int64 > maxNumber || n.Int64 < -maxNumber {
+			return reject(n, fmt.Sprintf("numbers must be integers between -%d and %d", maxNumber, maxNumber))
+		}
+	}
+
+	return common.FieldError{}, true
+}
+
+func checkBranch(tree *parse.Tree, n *parse.BranchNode, rangeDepth int) (common.FieldError, bool) {
+	for _, child := range []parse.Node{n.Pipe, n.List, n.ElseList} {
+		if field, ok := checkTree(tree, child, rangeDepth); !ok {
+			return field, false
+		}
+	}
+	return common.FieldError{}, true
+}
+
+func nodePosition(tree *parse.Tree, n parse.Node) (int, int) {
+	location, _ := tree.ErrorContext(n)
+	parts := strings.Split(location, ":")
+	if len(parts) < 3 {
+		return 0, 0
+	}
+
+	// The template packages count columns from zero.
+	line, _ := strconv.Atoi(parts[len(parts)-2])
+	column, _ := strconv.Atoi(parts[len(parts)-1])
+	return line, column + 1
+}
+
+// forbiddenMarkup matches elements, event handlers and URLs in the literal markup that
+// could run scripts or load other documents. Rendered pages are also served with
+// ContentSecurityPolicy, so this only gives authors early feedback.
+var forbiddenMarkup = regexp.MustCompile(`(?i)<\s*/?\s*(script|iframe|frame|frameset|object|embed|applet|base|link|meta|form|style)\b|\son[a-z]+\s*=|javascript:`)
+
+// ContentSecurityPolicy is sent with every page rendered from a custom template. It
+// blocks scripts, frames and forms and only allows inline styles and images.
+const ContentSecurityPolicy = "default-src 'none'; style-src 'unsafe-inline'; img-src data: https:; sandbox"
+
+// templateErrorPattern matches the "template: NAME:LINE[:COL]: message" errors of the
+// template packages.
+var templateErrorPattern = regexp.MustCompile(`^(?:html/)?template: ` + sandboxName + `:(\d+)(?::(\d+))?: (.*)$`)
+
+func sourceError(reason string, err error) common.FieldError {
+	field := common.FieldError{Field: "html", Reason: reason, Message: err.Error()}
+
+	var escapeErr *template.Error
+	if errors.As(err, &escapeErr) {
+		field.Line = escapeErr.Line
+		field.Message = escapeErr.Description
+		return field
+	}
+
+	if match := templateErrorPattern.FindStringSubmatch(err.Error()); match != nil {
+		field.Line, _ = strconv.Atoi(match[1])
+		if column, err := strconv.Atoi(match[2]); err == nil {
+			field.Column = column + 1
+		}
+		field.Message = match[3]
+	}
+	return field
+}
+
+// checkCSS only allows self-contained style sheets: nothing that can close the style
+// element or load other resources.
+func checkCSS(css string) error {
+	if len(css) > maxCSSSize {
+		return invalidTemplate(common.FieldError{Field: "css", Reason: "too_large", Message: fmt.Sprintf("must be at most %d bytes", maxCSSSize)})
+	}
+
+	lower := strings.ToLower(css)
+	for _, rule := range []struct{ token, message string }{
+		{"<", `"<" is not allowed`},
+		{"\\", "escape sequences are not allowed"},
+		{"@import", "@import is not allowed"},
+		{"expression(", "expression() is not allowed"},
+	} {
+		if i := strings.Index(lower, rule.token); i >= 0 {
+			return invalidCSS(css, i, rule.message)
+		}
+	}
+
+	for offset := 0; ; {
+		i := strings.Index(lower[offset:], "url(")
+		if i < 0 {
+			break
+		}
+		i += offset
+		value := strings.TrimLeft(lower[i+len("url("):], " \t\r\n\"'")
+		if !strings.HasPrefix(value, "data:") {
+			return invalidCSS(css, i, "url() may only reference data: URIs")
+		}
+		offset = i + len("url(")
+	}
+
+	return nil
+}
+
+func invalidCSS(css string, offset int, message string) error {
+	line := strings.Count(css[:offset], "\n") + 1
+	column := offset - strings.LastIndex(css[:offset], "\n")
+	return invalidTemplate(common.FieldError{Field: "css", Reason: "forbidden", Message: message, Line: line, Column: column})
+}
+
+func invalidTemplate(fields ...common.FieldError) error {
+	return common.NewValidationError(common.ErrInvalidTemplate, fields...)
+}
+
+func forbidden(name string) func(...any) (string, error) {
+	return func(...any) (string, error) {
+		return "", fmt.Errorf("function %q is not allowed", name)
+	}
+}
+
+func lines(text string) []string {
+	var result []string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// bullet strips a leading "-", "*" or "•" list marker from line.
+func bullet(line string) string {
+	for _, marker := range []string{"- ", "* ", "• "} {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(strings.TrimPrefix(line, marker))
+		}
+	}
+	return line
+}
+
+func formatDate(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+func dateRange(start time.Time, end *time.Time) string {
+	if start.IsZero() {
+		return ""
+	}
+
+	to := "Present"
+	if end != nil {
+		to = end.Format("Jan 2006")
+	}
+	return start.Format("Jan 2006") + " – " + to
+}
+
+func defaultString(fallback, value string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"embed"
+	"slices"
+
+	"github.com/hwangseonu/paperless.dev/internal/schema"
+)
+
+const (
+	SectionSkills     = "skills"
+	SectionExperience = "experience"
+	SectionEducation  = "education"
+	SectionProjects   = "projects"
+)
+
+// Default is used for resumes without a template.
+const Default = "modern"
+
+//go:embed thumbnails/*.svg
+var thumbnails embed.FS
+
+// Template describes a built-in resume layout. Sections lists the resume sections the
+// template renders, in the order they appear.
+type Template struct {
+	Name        string
+	Description string
+	Sections    []string
+	PageSize    string
+}
+
+var builtins = []Template{
+	{
+		Name:        "modern",
+		Description: "Single column layout with blue accents and ruled section headings.",
+		Sections:    []string{SectionSkills, SectionExperience, SectionProjects, SectionEducation},
+		PageSize:    "A4",
+	},
+	{
+		Name:        "classic",
+		Description: "Traditional black and white layout on US Letter paper.",
+		Sections:    []string{SectionExperience, SectionEducation, SectionProjects, SectionSkills},
+		PageSize:    "Letter",
+	},
+	{
+		Name:        "minimal",
+		Description: "Compact layout with generous margins and no projects section.",
+		Sections:    []string{SectionExperience, SectionEducation, SectionSkills},
+		PageSize:    "A4",
+	},
+}
+
+func All() []Template {
+	return builtins
+}
+
+func Lookup(name string) (*Template, bool) {
+	i := slices.IndexFunc(builtins, func(t Template) bool { return t.Name == name })
+	if i < 0 {
+		return nil, false
+	}
+	return &builtins[i], true
+}
+
+// Resolve returns the named template, falling back to the default for empty or unknown names.
+func Resolve(name string) *Template {
+	if template, ok := Lookup(name); ok {
+		return template
+	}
+	template, _ := Lookup(Default)
+	return template
+}
+
+// Thumbnail returns the SVG thumbnail of the named template.
+func Thumbnail(name string) ([]byte, bool) {
+	if _, ok := Lookup(name); !ok {
+		return nil, false
+	}
+
+	data, err := thumbnails.ReadFile("thumbnails/" + name + ".svg")
+	return data, err == nil
+}
+
+func (template *Template) Supports(section string) bool {
+	return slices.Contains(template.Sections, section)
+}
+
+func (template *Template) ResponseSchema() *schema.TemplateResponseSchema {
+	return &schema.TemplateResponseSchema{
+		Name:        template.Name,
+		Description: template.Description,
+		Sections:    template.Sections,
+		PageSize:    template.PageSize,
+		Thumbnail:   "/api/v1/templates/" + template.Name + "/thumbnail.svg",
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	restful "github.com/hwangseonu/gin-restful"
@@ -26,30 +27,138 @@ import (
 // @name Authorization
 // @description "Type 'Bearer ' followed by your API key"
 func main() {
+	config := common.GetConfig()
+
+	providers, err := auth.ParseProviderConfigs(config.OIDCProviders)
+	if err != nil {
+		log.Fatalln("invalid OIDC_PROVIDERS:", err)
+	}
+	oidc := auth.NewOIDC(providers)
+
+	rp, err := auth.NewRelyingParty(config)
+	if err != nil {
+		log.Fatalln("invalid WebAuthn configuration:", err)
+	}
+	passkeys := auth.NewPasskeys(rp)
+
 	engine := gin.Default()
 	docs.SwaggerInfo.BasePath = "/api/v1"
 
+	// Login throttling and the audit trail key on the client IP. X-Forwarded-For is only
+	// believed from the proxies listed in TRUSTED_PROXIES; by default no one is trusted.
+	var trustedProxies []string
+	if config.TrustedProxies != "" {
+		trustedProxies = strings.Split(config.TrustedProxies, ",")
+	}
+	if err := engine.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalln("invalid TRUSTED_PROXIES:", err)
+	}
+
 	protector := auth.NewProtector()
-	protector.RegisterAny("/api/v1/users/:id")
-	protector.Register("/api/v1/resumes", http.MethodPost)
-	protector.Register("/api/v1/resumes/:id", http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete)
+	protector.RegisterScoped("/api/v1/users/:id", auth.ScopeUserRead, http.MethodGet)
+	protector.Register("/api/v1/users/:id", http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete)
+	protector.Register("/api/v1/users/:id/password", http.MethodPost)
+	protector.Register("/api/v1/users/:id/tokens", http.MethodGet, http.MethodPost)
+	protector.Register("/api/v1/users/:id/tokens/:tokenID", http.MethodDelete)
+	protector.RegisterOptional("/api/v1/resumes", auth.ScopeResumesRead, http.MethodGet)
+	protector.RegisterOptional("/api/v1/resumes/:id", auth.ScopeResumesRead, http.MethodGet)
+	protector.RegisterOptional("/api/v1/resumes/:id/export.pdf", auth.ScopeResumesRead, http.MethodGet)
+	protector.RegisterOptional("/api/v1/resumes/:id/export", auth.ScopeResumesRead, http.MethodGet)
+	protector.RegisterScoped("/api/v1/resumes/import", auth.ScopeResumesWrite, http.MethodPost)
+	protector.RegisterScoped("/api/v1/resumes", auth.ScopeResumesWrite, http.MethodPost)
+	protector.RegisterScoped("/api/v1/resumes/:id", auth.ScopeResumesWrite, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete)
+	protector.RegisterOptional("/api/v1/templates", auth.ScopeResumesRead, http.MethodGet)
+	protector.RegisterOptional("/api/v1/templates/:id", auth.ScopeResumesRead, http.MethodGet)
+	protector.RegisterOptional("/api/v1/templates/:id/preview", auth.ScopeResumesRead, http.MethodGet)
+	protector.RegisterScoped("/api/v1/templates", auth.ScopeResumesWrite, http.MethodPost)
+	protector.RegisterScoped("/api/v1/templates/:id", auth.ScopeResumesWrite, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete)
+	protector.Register("/api/v1/auth/verify-email/resend", http.MethodPost)
+	protector.Register("/api/v1/auth/2fa/enroll", http.MethodPost)
+	protector.Register("/api/v1/auth/2fa/confirm", http.MethodPost)
+	protector.Register("/api/v1/auth/2fa/disable", http.MethodPost)
+	protector.Register("/api/v1/auth/passkeys/register/begin", http.MethodPost)
+	protector.Register("/api/v1/auth/passkeys/register/finish", http.MethodPost)
+	protector.Register("/api/v1/users/:id/passkeys", http.MethodGet)
+	protector.Register("/api/v1/users/:id/logins", http.MethodGet)
+	protector.Register("/api/v1/admin/impersonations", http.MethodPost)
+	protector.Register("/api/v1/oauth/authorize", http.MethodGet, http.MethodPost)
+	protector.Register("/api/v1/oauth/clients", http.MethodGet, http.MethodPost)
+	protector.Register("/api/v1/oauth/clients/:id", http.MethodDelete)
+	protector.Register("/api/v1/admin/audit-logs", http.MethodGet)
+	protector.Register("/api/v1/users/:id/passkeys/:passkeyID", http.MethodDelete)
 
 	engine.Use(protector.Middleware())
 	engine.Use(common.ErrorHandler)
 
+	resume := resource.NewResume()
+	template := resource.NewTemplate()
+
 	api := restful.NewAPI("/api/v1")
 	{
 		user := resource.NewUser()
-		resume := resource.NewResume()
 		api.RegisterResource("/users", user)
 		api.RegisterResource("/resumes", resume)
+		api.RegisterResource("/templates", template)
 		api.RegisterHandlers(&engine.RouterGroup)
 	}
 
+	engine.GET("/api/v1/resumes/:id/export.pdf", resume.ExportPDF)
+	engine.GET("/api/v1/resumes/:id/export", resume.Export)
+	engine.POST("/api/v1/resumes/import", resume.Import)
+	engine.GET("/api/v1/templates/:id/preview", template.Preview)
+	engine.GET("/api/v1/templates/:id/thumbnail.svg", template.Thumbnail)
+
+	engine.GET("/r/:id", resume.Page)
+	engine.GET("/u/:username/:slug", resume.PageBySlug)
+	engine.GET("/sitemap.xml", resume.Sitemap)
+
 	authGroup := engine.Group("/api/v1/auth")
 	{
 		authGroup.POST("/login", auth.LoginHandler)
+		authGroup.POST("/login/2fa", auth.TwoFactorLoginHandler)
 		authGroup.POST("/refresh", auth.RefreshHandler)
+		authGroup.POST("/logout", auth.LogoutHandler)
+		authGroup.POST("/verify-email", auth.VerifyEmailHandler)
+		authGroup.POST("/verify-email/resend", auth.ResendVerificationHandler)
+		authGroup.POST("/password/forgot", auth.ForgotPasswordHandler)
+		authGroup.POST("/password/reset", auth.ResetPasswordHandler)
+		authGroup.POST("/magic-link", auth.MagicLinkHandler)
+		authGroup.POST("/magic-link/verify", auth.VerifyMagicLinkHandler)
+		authGroup.POST("/2fa/enroll", auth.EnrollTwoFactorHandler)
+		authGroup.POST("/2fa/confirm", auth.ConfirmTwoFactorHandler)
+		authGroup.POST("/2fa/disable", auth.DisableTwoFactorHandler)
+		authGroup.GET("/oidc/:provider/login", oidc.LoginHandler)
+		authGroup.GET("/oidc/:provider/callback", oidc.CallbackHandler)
+		authGroup.POST("/passkeys/register/begin", passkeys.BeginRegistrationHandler)
+		authGroup.POST("/passkeys/register/finish", passkeys.FinishRegistrationHandler)
+		authGroup.POST("/passkeys/login/begin", passkeys.BeginLoginHandler)
+		authGroup.POST("/passkeys/login/finish", passkeys.FinishLoginHandler)
+	}
+
+	engine.POST("/api/v1/users/:id/password", auth.ChangePasswordHandler)
+	engine.GET("/api/v1/users/:id/tokens", auth.ListAccessTokensHandler)
+	engine.POST("/api/v1/users/:id/tokens", auth.CreateAccessTokenHandler)
+	engine.DELETE("/api/v1/users/:id/tokens/:tokenID", auth.RevokeAccessTokenHandler)
+	engine.GET("/api/v1/users/:id/logins", auth.ListLoginsHandler)
+	engine.GET("/api/v1/users/:id/passkeys", auth.ListPasskeysHandler)
+	engine.DELETE("/api/v1/users/:id/passkeys/:passkeyID", auth.DeletePasskeyHandler)
+
+	oauthGroup := engine.Group("/api/v1/oauth")
+	{
+		oauthGroup.GET("/authorize", auth.AuthorizeConsentHandler)
+		oauthGroup.POST("/authorize", auth.AuthorizeDecisionHandler)
+		oauthGroup.POST("/token", auth.TokenHandler)
+		oauthGroup.POST("/introspect", auth.IntrospectHandler)
+		oauthGroup.POST("/revoke", auth.RevokeHandler)
+		oauthGroup.GET("/clients", auth.ListOAuthClientsHandler)
+		oauthGroup.POST("/clients", auth.CreateOAuthClientHandler)
+		oauthGroup.DELETE("/clients/:id", auth.DeleteOAuthClientHandler)
+	}
+
+	adminGroup := engine.Group("/api/v1/admin")
+	{
+		adminGroup.POST("/impersonations", auth.ImpersonateHandler)
+		adminGroup.GET("/audit-logs", auth.ListAuditLogsHandler)
 	}
 
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -4,6 +4,7 @@ export const ENDPOINTS = {
   AUTH: {
     LOGIN: '/api/v1/auth/login',
     REFRESH_TOKEN: '/api/v1/auth/refresh',
+    LOGOUT: '/api/v1/auth/logout',
   },
   USER: {
     WITHOUT_ID: '/api/v1/users',
@@ -1,11 +1,18 @@
 import axios from 'axios'
 import { BASE_URL } from '@/components/config/api.ts'
 
+// Tokens are kept in HttpOnly cookies set by the API. The readable csrf_token cookie
+// is echoed back in X-CSRF-Token for unsafe requests.
 const api = axios.create({
   baseURL: BASE_URL,
   timeout: 10000,
+  withCredentials: true,
+  withXSRFToken: true,
+  xsrfCookieName: 'csrf_token',
+  xsrfHeaderName: 'X-CSRF-Token',
   headers: {
     'Content-Type': 'application/json',
+    'X-Auth-Mode': 'cookie',
   },
 })
 